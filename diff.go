@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// maxDiffCells bounds the size of the LCS table. Beyond it the differing
+// middle section is reported as a single replacement.
+const maxDiffCells = 4 << 20
+
+// unifiedDiff returns a unified diff turning a into b, or nil if they are equal.
+func unifiedDiff(aName, bName string, a, b []byte) []byte {
+	if bytes.Equal(a, b) {
+		return nil
+	}
+	aLines, bLines := splitLines(a), splitLines(b)
+	ops := diffLines(aLines, bLines)
+
+	out := new(bytes.Buffer)
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", aName, bName)
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// Extend the hunk while changes are close enough to share context.
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*contextLines {
+				break
+			}
+		}
+		lo, hi := start-contextLines, end+contextLines
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(ops) {
+			hi = len(ops)
+		}
+		writeHunk(out, ops[lo:hi])
+		start = hi
+	}
+	return out.Bytes()
+}
+
+// diffOp is one line of a diff: ' ' for a shared line, '-' for a line only
+// in the old text and '+' for a line only in the new text. aLine and bLine
+// are the 1-based line numbers the op starts at in each text.
+type diffOp struct {
+	kind         byte
+	text         string
+	aLine, bLine int
+}
+
+func writeHunk(out *bytes.Buffer, ops []diffOp) {
+	var aCount, bCount int
+	for _, op := range ops {
+		if op.kind != '+' {
+			aCount++
+		}
+		if op.kind != '-' {
+			bCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(ops[0].aLine, aCount), hunkRange(ops[0].bLine, bCount))
+	for _, op := range ops {
+		fmt.Fprintf(out, "%c%s\n", op.kind, op.text)
+	}
+}
+
+// hunkRange formats a hunk range; an empty range names the line before it.
+func hunkRange(line, count int) string {
+	if count == 0 {
+		line--
+	}
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func splitLines(b []byte) []string {
+	s := strings.TrimSuffix(string(b), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines computes a line diff from the longest common subsequence of the
+// two texts, after trimming their common prefix and suffix.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	var ops []diffOp
+	ai, bi := 1, 1
+	emit := func(kind byte, text string) {
+		ops = append(ops, diffOp{kind: kind, text: text, aLine: ai, bLine: bi})
+		if kind != '+' {
+			ai++
+		}
+		if kind != '-' {
+			bi++
+		}
+	}
+	for _, line := range a[:prefix] {
+		emit(' ', line)
+	}
+
+	if (len(am)+1)*(len(bm)+1) > maxDiffCells {
+		for _, line := range am {
+			emit('-', line)
+		}
+		for _, line := range bm {
+			emit('+', line)
+		}
+	} else {
+		// lcs[i][j] is the length of the LCS of am[i:] and bm[j:].
+		lcs := make([][]int, len(am)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(bm)+1)
+		}
+		for i := len(am) - 1; i >= 0; i-- {
+			for j := len(bm) - 1; j >= 0; j-- {
+				if am[i] == bm[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(am) || j < len(bm) {
+			switch {
+			case i < len(am) && j < len(bm) && am[i] == bm[j]:
+				emit(' ', am[i])
+				i++
+				j++
+			case j == len(bm) || (i < len(am) && lcs[i+1][j] >= lcs[i][j+1]):
+				emit('-', am[i])
+				i++
+			default:
+				emit('+', bm[j])
+				j++
+			}
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		emit(' ', line)
+	}
+	return ops
+}
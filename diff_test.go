@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"change", "1\n2\n3\n4\n5\n6\n7\n8\n", "1\n2\n3\n4\nfive\n6\n7\n8\n", `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`},
+		{"insert", "x\ny\n", "x\nnew\ny\n", `--- a
++++ b
+@@ -1,2 +1,3 @@
+ x
++new
+ y
+`},
+		{"from empty", "", "x\n", `--- a
++++ b
+@@ -0,0 +1 @@
++x
+`},
+		{"two hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n", "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n", `--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`},
+	}
+	for _, test := range tests {
+		got := string(unifiedDiff("a", "b", []byte(test.a), []byte(test.b)))
+		if got != test.want {
+			t.Errorf("%s: got\n%s\nexpected\n%s", test.name, got, test.want)
+		}
+	}
+}
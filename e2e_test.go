@@ -25,30 +25,49 @@ import (
 // we run stringer -type X and then compile and run the program. The resulting
 // binary panics if the String method for X is not correct, including for error cases.
 
+// stringer is the errorer binary built by TestMain.
+var stringer string
+
+// TestMain builds errorer once in a temporary directory for all the tests.
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "stringer")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	stringer = filepath.Join(dir, "stringer.exe")
+	code := 1
+	if err := run("go", "build", "-o", stringer); err != nil {
+		fmt.Fprintf(os.Stderr, "building stringer: %s\n", err)
+	} else {
+		code = m.Run()
+	}
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestEndToEnd(t *testing.T) {
 	dir, err := ioutil.TempDir("", "stringer")
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer os.RemoveAll(dir)
-	// Create stringer in temporary directory.
-	stringer := filepath.Join(dir, "stringer.exe")
-	err = run("go", "build", "-o", stringer)
-	if err != nil {
-		t.Fatalf("building stringer: %s", err)
-	}
 	// Read the testdata directory.
-	fd, err := os.Open("fixtures")
+	fd, err := os.Open("testdata")
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer fd.Close()
-	names, err := fd.Readdirnames(-1)
+	infos, err := fd.Readdir(-1)
 	if err != nil {
-		t.Fatalf("Readdirnames: %s", err)
+		t.Fatalf("Readdir: %s", err)
 	}
 	// Generate, compile, and run the test programs.
-	for _, name := range names {
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() {
+			continue
+		}
 		if !strings.HasSuffix(name, ".go") {
 			t.Errorf("%s is not a Go file", name)
 			continue
@@ -66,22 +85,51 @@ func TestEndToEnd(t *testing.T) {
 			transformNameMethod = "snake"
 		}
 
-		stringerCompileAndRun(t, dir, stringer, typeName, name, transformNameMethod)
+		if name == "multi.go" {
+			typeName = "Multi,Status,Reason"
+		}
+
+		var flags []string
+		switch name {
+		case "errinfo.go":
+			flags = []string{"-proto-domain=errors.example.com"}
+		case "problem.go":
+			flags = []string{"-json=rfc7807"}
+		case "renamed.go":
+			flags = []string{"-json-fields=code:error,msg:detail"}
+		case "text.go":
+			flags = []string{"-text"}
+		case "sqlname.go":
+			flags = []string{"-sql=name"}
+		case "sqlint.go":
+			flags = []string{"-sql=int"}
+		case "transform.go":
+			flags = []string{"-ignore-case", "-proto-domain=errors.example.com"}
+		case "strcode.go":
+			flags = []string{"-text", "-sql=name", "-ignore-case", "-proto-domain=errors.example.com"}
+		}
+
+		stringerCompileAndRun(t, dir, stringer, typeName, name, transformNameMethod, flags...)
 	}
 }
 
 // stringerCompileAndRun runs stringer for the named file and compiles and
 // runs the target binary in directory dir. That binary will panic if the String method is incorrect.
-func stringerCompileAndRun(t *testing.T, dir, stringer, typeName, fileName, transformNameMethod string) {
+// flags are passed on to stringer.
+func stringerCompileAndRun(t *testing.T, dir, stringer, typeName, fileName, transformNameMethod string, flags ...string) {
 	t.Logf("run: %s %s\n", fileName, typeName)
 	source := filepath.Join(dir, fileName)
-	err := copy(source, filepath.Join("fixtures", fileName))
+	err := copy(source, filepath.Join("testdata", fileName))
 	if err != nil {
 		t.Fatalf("copying file to temporary directory: %s", err)
 	}
-	stringSource := filepath.Join(dir, typeName+"_string.go")
+	stringSource := filepath.Join(dir, strings.TrimSuffix(fileName, ".go")+"_string.go")
 	// Run stringer in temporary directory.
-	err = run(stringer, "-type", typeName, "-output", stringSource, source)
+	args := append([]string{"-type", typeName, "-output", stringSource}, flags...)
+	if transformNameMethod != "noop" {
+		args = append(args, "-transform="+transformNameMethod)
+	}
+	err = run(stringer, append(args, source)...)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -90,6 +138,121 @@ func stringerCompileAndRun(t *testing.T, dir, stringer, typeName, fileName, tran
 	if err != nil {
 		t.Fatal(err)
 	}
+	// The output just written must pass -check.
+	err = run(stringer, append(append([]string{"-check"}, args...), source)...)
+	if err != nil {
+		t.Fatalf("check after generating: %s", err)
+	}
+}
+
+// TestCheck verifies that -check fails once the source changes without
+// regenerating, and leaves the output file alone.
+func TestCheck(t *testing.T) {
+	dir, source := copyError(t)
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "error_string.go")
+	if err := run(stringer, "-type", "Error", "-output", output, source); err != nil {
+		t.Fatal(err)
+	}
+	before, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(source, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString("\nconst Forgotten Error = 99 // Nobody ran go generate\n")
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(stringer, "-check", "-type", "Error", "-output", output, source)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("check passed for an out of date file")
+	}
+	if !strings.Contains(string(out), "Forgotten") {
+		t.Errorf("diff does not mention the new constant:\n%s", out)
+	}
+	after, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("check modified the output file")
+	}
+}
+
+// TestLock verifies that -lock fails once a locked code is renumbered, and
+// that -allow-breaking accepts the change.
+func TestLock(t *testing.T) {
+	dir, source := copyError(t)
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "error_string.go")
+	lock := filepath.Join(dir, "error_codes.lock")
+	args := []string{"-type", "Error", "-output", output, "-lock", lock, source}
+	if err := run(stringer, args...); err != nil {
+		t.Fatal(err)
+	}
+	before, err := ioutil.ReadFile(lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(before), "\nError.WorksOnMyMachine 4\n") {
+		t.Fatalf("lock file does not record WorksOnMyMachine:\n%s", before)
+	}
+
+	src, err := ioutil.ReadFile(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src = []byte(strings.Replace(string(src), "\tNotSure ", "\tInserted // Inserted in the middle\n\tNotSure ", 1))
+	if err := ioutil.WriteFile(source, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(stringer, args...).CombinedOutput()
+	if err == nil {
+		t.Fatal("renumbering a locked code succeeded")
+	}
+	if !strings.Contains(string(out), "Error.WorksOnMyMachine changed from 4 to 5") {
+		t.Errorf("output does not report the renumbered code:\n%s", out)
+	}
+	after, err := ioutil.ReadFile(lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("failed run modified the lock file")
+	}
+
+	if err := run(stringer, append([]string{"-allow-breaking"}, args...)...); err != nil {
+		t.Fatalf("-allow-breaking: %s", err)
+	}
+	after, err = ioutil.ReadFile(lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(after), "\nError.WorksOnMyMachine 5\n") {
+		t.Errorf("-allow-breaking did not update the lock file:\n%s", after)
+	}
+}
+
+// copyError copies testdata/error.go into a new temporary directory, which
+// the caller removes, and returns the directory and the copy.
+func copyError(t *testing.T) (dir, source string) {
+	dir, err := ioutil.TempDir("", "stringer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source = filepath.Join(dir, "error.go")
+	if err := copy(source, filepath.Join("testdata", "error.go")); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir, source
 }
 
 // copy copies the from file to the to file.
@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// annotationPrefix marks a comment line as errorer annotations rather than
+// message text, e.g.
+//
+//	NotFound Error = iota //errorer:http=404 grpc=NotFound msg="User could not be found"
+const annotationPrefix = "//errorer:"
+
+// grpcCodes maps the names of the canonical gRPC status codes to their
+// numeric values, so annotations can use either form without the generated
+// code depending on google.golang.org/grpc.
+var grpcCodes = map[string]uint32{
+	"OK":                 0,
+	"Canceled":           1,
+	"Unknown":            2,
+	"InvalidArgument":    3,
+	"DeadlineExceeded":   4,
+	"NotFound":           5,
+	"AlreadyExists":      6,
+	"PermissionDenied":   7,
+	"ResourceExhausted":  8,
+	"FailedPrecondition": 9,
+	"Aborted":            10,
+	"OutOfRange":         11,
+	"Unimplemented":      12,
+	"Internal":           13,
+	"Unavailable":        14,
+	"DataLoss":           15,
+	"Unauthenticated":    16,
+}
+
+// constantComment returns the message, detail and annotations of a constant.
+// The message comes from the trailing comment or, if that has none, from the
+// doc comment: the spec's, or the declaration's for an unparenthesized
+// const. Doc comment lines are joined into one line. Annotations may be in
+// either comment; those of the trailing comment win.
+//
+// A detail annotation without a value splits the message after its first
+// sentence, keeping the rest as the detail; one with a value is the detail.
+func constantComment(decl *ast.GenDecl, vspec *ast.ValueSpec) (string, string, map[string]string, error) {
+	msg, annotations, err := parseComment(vspec.Comment)
+	if err != nil {
+		return "", "", nil, err
+	}
+	doc := vspec.Doc
+	if doc == nil && !decl.Lparen.IsValid() {
+		doc = decl.Doc
+	}
+	docMsg, docAnnotations, err := parseComment(doc)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if _, ok := annotations["msg"]; !ok && strings.TrimSpace(msg) == "" {
+		msg = strings.Join(strings.Fields(docMsg), " ")
+	}
+	for key, val := range docAnnotations {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		if _, ok := annotations[key]; !ok {
+			annotations[key] = val
+		}
+	}
+
+	detail, ok := annotations["detail"]
+	if ok && detail == "" {
+		msg, detail = splitSentence(msg)
+	}
+	return msg, detail, annotations, nil
+}
+
+// splitSentence splits s after its first sentence, which ends at a period
+// followed by a space. The period itself is dropped, as error messages do
+// not end in one.
+func splitSentence(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+2:])
+	}
+	return strings.TrimSuffix(s, "."), ""
+}
+
+// hasDetails reports whether any value has a detail.
+func hasDetails(runs [][]Value) bool {
+	for _, run := range runs {
+		for _, v := range run {
+			if v.detail != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseComment splits a constant's comment into its message and annotations.
+// Annotation lines are dropped from the message; a msg annotation replaces it.
+func parseComment(group *ast.CommentGroup) (string, map[string]string, error) {
+	if group == nil {
+		return "", nil, nil
+	}
+	var annotations map[string]string
+	text := new(ast.CommentGroup)
+	for _, c := range group.List {
+		if !strings.HasPrefix(c.Text, annotationPrefix) {
+			text.List = append(text.List, c)
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		if err := parseAnnotations(c.Text[len(annotationPrefix):], annotations); err != nil {
+			return "", nil, err
+		}
+	}
+	msg := text.Text()
+	if m, ok := annotations["msg"]; ok {
+		msg = m
+	}
+	return msg, annotations, nil
+}
+
+// parseAnnotations parses space-separated key=value pairs into annotations.
+// Values may be double-quoted Go strings; a key on its own is recorded with
+// an empty value.
+func parseAnnotations(s string, annotations map[string]string) error {
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil
+		}
+		end := strings.IndexAny(s, "= \t")
+		if end < 0 {
+			end = len(s)
+		}
+		key := s[:end]
+		if key == "" {
+			return fmt.Errorf("missing annotation key in %q", s)
+		}
+		s = s[end:]
+		if !strings.HasPrefix(s, "=") {
+			annotations[key] = ""
+			continue
+		}
+		s = s[1:]
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return fmt.Errorf("bad quoted value for %s: %s", key, err)
+			}
+			val, _ = strconv.Unquote(quoted)
+			s = s[len(quoted):]
+		} else {
+			end := strings.IndexAny(s, " \t")
+			if end < 0 {
+				end = len(s)
+			}
+			val, s = s[:end], s[end:]
+		}
+		annotations[key] = val
+	}
+}
+
+// checkCodeAnnotations validates the code, http and grpc annotations of v.
+func checkCodeAnnotations(v *Value) error {
+	for _, key := range []string{"code", "http"} {
+		if s, ok := v.annotations[key]; ok {
+			if _, err := strconv.Atoi(s); err != nil {
+				return fmt.Errorf("%s: %s=%q is not an integer", v.name, key, s)
+			}
+		}
+	}
+	if s, ok := v.annotations["grpc"]; ok {
+		if _, err := grpcCode(s); err != nil {
+			return fmt.Errorf("%s: %s", v.name, err)
+		}
+	}
+	return nil
+}
+
+// grpcCode resolves a grpc annotation given either as a code name or number.
+func grpcCode(s string) (uint32, error) {
+	if code, ok := grpcCodes[s]; ok {
+		return code, nil
+	}
+	code, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || code > 16 {
+		return 0, fmt.Errorf("grpc=%q is not a gRPC status code", s)
+	}
+	return uint32(code), nil
+}
+
+// hasCodeAnnotations reports whether any value carries a code, http or grpc annotation.
+func hasCodeAnnotations(runs [][]Value) bool {
+	for _, run := range runs {
+		for _, v := range run {
+			for _, key := range []string{"code", "http", "grpc"} {
+				if _, ok := v.annotations[key]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// allHaveAnnotation reports whether every value carries the annotation key.
+func allHaveAnnotation(runs [][]Value, key string) bool {
+	for _, run := range runs {
+		for _, v := range run {
+			if _, ok := v.annotations[key]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// buildCodeMethods generates Code, HTTPStatus and GRPCCode from the annotations.
+// Constants without an annotation fall back to their own value, 500 and Unknown.
+// A string code is its own identifier, so Code is only generated for string
+// types when every constant has a code annotation, and is -1 for values
+// that are not constants.
+func (g *Generator) buildCodeMethods(runs [][]Value, typeName string) {
+	code := "int(i)"
+	if runs[0][0].isString {
+		code = "-1"
+	}
+	if !runs[0][0].isString || allHaveAnnotation(runs, "code") {
+		g.buildAnnotationSwitch(runs, typeName, "Code", "int", "code", code, func(s string) string {
+			return s
+		})
+	}
+	g.buildAnnotationSwitch(runs, typeName, "HTTPStatus", "int", "http", "500", func(s string) string {
+		return s
+	})
+	g.buildAnnotationSwitch(runs, typeName, "GRPCCode", "uint32", "grpc", "2", func(s string) string {
+		code, _ := grpcCode(s)
+		return strconv.FormatUint(uint64(code), 10)
+	})
+}
+
+// buildAnnotationSwitch generates a method returning the annotation key of
+// each constant, rendered as a Go expression by expr.
+func (g *Generator) buildAnnotationSwitch(runs [][]Value, typeName, methodName, resultType, key, fallback string, expr func(string) string) {
+	g.Printf("\nfunc (i %s) %s() %s {\n", typeName, methodName, resultType)
+	g.Printf("\tswitch i {\n")
+	for _, values := range runs {
+		for _, value := range values {
+			if s, ok := value.annotations[key]; ok {
+				g.Printf("\tcase %s:\n", value.name)
+				g.Printf("\t\treturn %s\n", expr(s))
+			}
+		}
+	}
+	g.Printf("\t}\n")
+	g.Printf("\treturn %s\n", fallback)
+	g.Printf("}\n")
+}
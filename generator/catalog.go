@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// Enum describes an error type and its constants, for outputs other than
+// the generated Go code.
+type Enum struct {
+	Type      string     `json:"type"`
+	Constants []Constant `json:"constants"`
+}
+
+// Constant describes one declared constant of an Enum.
+type Constant struct {
+	Name        string            `json:"name"`
+	String      string            `json:"string,omitempty"` // Name returned by String, if transformed.
+	Value       Literal           `json:"value"`
+	Message     string            `json:"message"`
+	Detail      string            `json:"detail,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	File        string            `json:"file"` // Base name of the declaring file.
+	Line        int               `json:"line"`
+}
+
+// Literal is a constant value as a JSON number, or a JSON string for string
+// codes.
+type Literal string
+
+// MarshalJSON returns l itself.
+func (l Literal) MarshalJSON() ([]byte, error) {
+	if !json.Valid([]byte(l)) {
+		return nil, fmt.Errorf("bad constant value %s", string(l))
+	}
+	return []byte(l), nil
+}
+
+// UnmarshalJSON sets l to a copy of data.
+func (l *Literal) UnmarshalJSON(data []byte) error {
+	*l = Literal(data)
+	return nil
+}
+
+// stringValue returns the value of a string code, and whether l is one.
+func (l Literal) stringValue() (string, bool) {
+	var s string
+	err := json.Unmarshal([]byte(l), &s)
+	return s, err == nil
+}
+
+// wireName returns the name of c as MarshalJSON writes it: the value of a
+// string code, or the name returned by String.
+func (c Constant) wireName() string {
+	if s, ok := c.Value.stringValue(); ok {
+		return s
+	}
+	if c.String != "" {
+		return c.String
+	}
+	return c.Name
+}
+
+// Load parses the package described by cfg and describes each of cfg.Types.
+// Constants are listed in declaration order, aliases included.
+func Load(cfg Config) ([]Enum, error) {
+	if len(cfg.Types) == 0 {
+		return nil, errors.New("no type names given")
+	}
+	transform, err := transformName(cfg.Transform)
+	if err != nil {
+		return nil, err
+	}
+	var g Generator
+	if err := g.ParsePackage(cfg); err != nil {
+		return nil, err
+	}
+	enums := make([]Enum, 0, len(cfg.Types))
+	for _, typeName := range cfg.Types {
+		e, err := g.Enum(typeName)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Transform != "" {
+			for i := range e.Constants {
+				e.Constants[i].String = transform(e.Constants[i].Name)
+			}
+		}
+		enums = append(enums, e)
+	}
+	return enums, nil
+}
+
+// Enum describes the named type of the parsed package, collecting its
+// constants as Generate does.
+func (g *Generator) Enum(typeName string) (Enum, error) {
+	values, err := g.collect(typeName)
+	if err != nil {
+		return Enum{}, err
+	}
+	return newEnum(typeName, values), nil
+}
+
+func newEnum(typeName string, values []Value) Enum {
+	e := Enum{Type: typeName}
+	for _, v := range values {
+		value := Literal(v.str)
+		if v.isString {
+			value = jsonString(v.stringValue())
+		}
+		e.Constants = append(e.Constants, Constant{
+			Name:        v.name,
+			Value:       value,
+			Message:     valueMsg(v),
+			Detail:      v.detail,
+			Annotations: v.annotations,
+			File:        filepath.Base(v.pos.Filename),
+			Line:        v.pos.Line,
+		})
+	}
+	return e
+}
+
+// jsonString returns s as a JSON string, without escaping HTML.
+func jsonString(s string) Literal {
+	b := new(bytes.Buffer)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return Literal(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+}
+
+// WriteCatalog writes enums to w as a JSON or YAML document, according to
+// format.
+func WriteCatalog(w io.Writer, enums []Enum, format string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(enums, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case "yaml":
+		_, err := w.Write(catalogYAML(enums))
+		return err
+	}
+	return fmt.Errorf("unknown catalog format %q", format)
+}
+
+// CatalogFormat returns the catalog format implied by the extension of name.
+func CatalogFormat(name string) string {
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
+
+// catalogYAML renders the catalog as YAML. Strings are written as JSON
+// strings, which YAML accepts as double-quoted scalars.
+func catalogYAML(enums []Enum) []byte {
+	b := new(bytes.Buffer)
+	quote := func(s string) string {
+		q, _ := json.Marshal(s)
+		return string(q)
+	}
+	for _, e := range enums {
+		fmt.Fprintf(b, "- type: %s\n", quote(e.Type))
+		fmt.Fprintf(b, "  constants:\n")
+		for _, c := range e.Constants {
+			fmt.Fprintf(b, "    - name: %s\n", quote(c.Name))
+			if c.String != "" {
+				fmt.Fprintf(b, "      string: %s\n", quote(c.String))
+			}
+			fmt.Fprintf(b, "      value: %s\n", c.Value)
+			fmt.Fprintf(b, "      message: %s\n", quote(c.Message))
+			if c.Detail != "" {
+				fmt.Fprintf(b, "      detail: %s\n", quote(c.Detail))
+			}
+			if len(c.Annotations) > 0 {
+				keys := make([]string, 0, len(c.Annotations))
+				for k := range c.Annotations {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				fmt.Fprintf(b, "      annotations:\n")
+				for _, k := range keys {
+					fmt.Fprintf(b, "        %s: %s\n", quote(k), quote(c.Annotations[k]))
+				}
+			}
+			fmt.Fprintf(b, "      file: %s\n", quote(c.File))
+			fmt.Fprintf(b, "      line: %d\n", c.Line)
+		}
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// generatePrefix starts the comments run by go generate.
+const generatePrefix = "//go:generate "
+
+// Directive is a //go:generate comment running errorer.
+type Directive struct {
+	Pos   token.Pos // Position of the comment.
+	Types []string  // Value of the -type flag.
+	Args  []string  // Arguments of errorer, flags included.
+}
+
+// Directives returns the //go:generate comments in files that run errorer,
+// either as an installed binary or with go run. Those without a -type flag
+// are left out, as errorer would reject them.
+func Directives(files []*ast.File) []Directive {
+	var directives []Directive
+	for _, file := range files {
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if d, ok := parseDirective(c); ok {
+					directives = append(directives, d)
+				}
+			}
+		}
+	}
+	return directives
+}
+
+// parseDirective recognizes a go generate command whose program, or the
+// package given to go run, is named errorer.
+func parseDirective(c *ast.Comment) (Directive, bool) {
+	if !strings.HasPrefix(c.Text, generatePrefix) {
+		return Directive{}, false
+	}
+	words := strings.Fields(c.Text[len(generatePrefix):])
+	i := 0
+	for ; i < len(words) && !strings.HasPrefix(words[i], "-"); i++ {
+		name := words[i]
+		if at := strings.Index(name, "@"); at >= 0 {
+			name = name[:at]
+		}
+		if path.Base(name) == "errorer" {
+			break
+		}
+	}
+	if i == len(words) || strings.HasPrefix(words[i], "-") {
+		return Directive{}, false
+	}
+	d := Directive{Pos: c.Pos(), Args: words[i+1:]}
+	for j := 0; j < len(d.Args); j++ {
+		name := strings.TrimLeft(d.Args[j], "-")
+		value := ""
+		if k := strings.Index(name, "="); k >= 0 {
+			name, value = name[:k], name[k+1:]
+		} else if name == "type" && j+1 < len(d.Args) {
+			j++
+			value = d.Args[j]
+		}
+		if name == "type" {
+			d.Types = strings.Split(strings.Trim(value, `"`), ",")
+		}
+	}
+	return d, d.Types != nil
+}
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Envelope describes the JSON object produced by the generated MarshalJSON
+// methods. The zero Envelope is {"type": <name>, "message": <message>}.
+type Envelope struct {
+	// Shape is "" for the plain object, or "rfc7807" for an RFC 7807
+	// problem details object with type, title, status, detail and instance
+	// members. The code's *<Type>Err wrapper then also marshals, using its
+	// cause as the detail and its "instance" field as the instance.
+	Shape string
+	// Fields maps "code" and "msg" to the JSON keys holding the constant
+	// name and message, overriding those of the shape.
+	Fields map[string]string
+}
+
+// jsonKey matches the JSON keys allowed by -json-fields, which are written
+// into generated string literals and struct tags.
+var jsonKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ParseEnvelopeFields parses a list of field:key pairs such as
+// "code:type,msg:message" into Envelope.Fields.
+func ParseEnvelopeFields(s string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.Index(pair, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("JSON field %q is not of the form field:key", pair)
+		}
+		fields[strings.TrimSpace(pair[:i])] = strings.TrimSpace(pair[i+1:])
+	}
+	return fields, nil
+}
+
+// envelopeMember is a member of the JSON envelope.
+type envelopeMember struct {
+	field    string // Generator field: "code", "msg" or, for RFC 7807, the key itself.
+	key      string // JSON key.
+	typ      string // JSON type: "string" or "integer".
+	required bool   // Whether the member is always present.
+}
+
+// members returns the members of the envelope in output order, after
+// checking its shape and field names.
+func (e Envelope) members() ([]envelopeMember, error) {
+	var members []envelopeMember
+	switch e.Shape {
+	case "":
+		members = []envelopeMember{
+			{"code", "type", "string", true},
+			{"msg", "message", "string", true},
+		}
+	case "rfc7807":
+		members = []envelopeMember{
+			{"code", "type", "string", true},
+			{"msg", "title", "string", true},
+			{"status", "status", "integer", true},
+			{"detail", "detail", "string", false},
+			{"instance", "instance", "string", false},
+		}
+	default:
+		return nil, fmt.Errorf("unknown JSON shape %q", e.Shape)
+	}
+	for field, key := range e.Fields {
+		if field != "code" && field != "msg" {
+			return nil, fmt.Errorf("unknown JSON field %q; want code or msg", field)
+		}
+		if !jsonKey.MatchString(key) {
+			return nil, fmt.Errorf("bad JSON key %q for %s", key, field)
+		}
+	}
+	seen := make(map[string]bool)
+	for i := range members {
+		m := &members[i]
+		if key, ok := e.Fields[m.field]; ok {
+			m.key = key
+		}
+		if seen[m.key] {
+			return nil, fmt.Errorf("JSON key %q is used twice", m.key)
+		}
+		seen[m.key] = true
+	}
+	return members, nil
+}
+
+// keys returns the JSON keys of the constant name and message.
+func (e Envelope) keys() (code, msg string, err error) {
+	members, err := e.members()
+	if err != nil {
+		return "", "", err
+	}
+	return members[0].key, members[1].key, nil
+}
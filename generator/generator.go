@@ -0,0 +1,842 @@
+// Package generator implements errorer: it parses a package and generates
+// String, Error and JSON methods for the named integer or string error types.
+//
+// Adapted from github.com/golang/tools/cmd/stringer
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	exact "go/constant"
+	"go/format"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Config describes a single run of the generator.
+type Config struct {
+	Types []string // Names of the types to generate; must be set.
+	Dir   string   // Directory holding the package; defaults to ".".
+	Files []string // Files making up the package; used instead of Dir if set.
+	Args  []string // Command line recorded in the header of the output.
+	Tags  []string // Build tags to apply when loading the package.
+	Mod   string   // Module download mode passed to the go command as -mod.
+	Tests bool     // Whether to include _test.go files in the package.
+
+	// ProtoDomain is the domain of the google.rpc.ErrorInfo-style details
+	// the generated ErrorInfo methods produce. They are generated only if set.
+	ProtoDomain string
+
+	JSON Envelope // Shape of the JSON envelope.
+	Text bool     // Whether to generate MarshalText and UnmarshalText.
+	SQL  string   // How Value and Scan store the codes: "name" or "int"; not generated if empty.
+
+	// Transform rewrites the names returned by String and used in JSON:
+	// "snake", "kebab", "upper-snake" or "camel". Empty keeps the Go names.
+	Transform string
+	// IgnoreCase makes <Type>String also accept names differing in case
+	// and in "_" or "-" separators, such as "not_found" for NotFound.
+	IgnoreCase bool
+}
+
+// Generate parses the package described by cfg and returns the gofmt-ed
+// source declaring the methods for each of cfg.Types.
+func Generate(cfg Config) ([]byte, error) {
+	if len(cfg.Types) == 0 {
+		return nil, errors.New("no type names given")
+	}
+	if _, err := cfg.JSON.members(); err != nil {
+		return nil, err
+	}
+	if err := checkSQLMode(cfg.SQL); err != nil {
+		return nil, err
+	}
+	if _, err := transformName(cfg.Transform); err != nil {
+		return nil, err
+	}
+	g := Generator{opts: cfg}
+	if err := g.ParsePackage(cfg); err != nil {
+		return nil, err
+	}
+	for _, typeName := range cfg.Types {
+		if err := g.Generate(typeName); err != nil {
+			return nil, err
+		}
+	}
+	g.PrintHeader(cfg.Args)
+	return g.Format()
+}
+
+// Diagnostic is an error found in the package being processed.
+type Diagnostic struct {
+	Pos token.Position // Position of the offending code, if known.
+	Msg string
+}
+
+func (d *Diagnostic) Error() string {
+	if !d.Pos.IsValid() {
+		return d.Msg
+	}
+	return fmt.Sprintf("%s: %s", d.Pos, d.Msg)
+}
+
+// Generator holds the state of the analysis. Primarily used to buffer
+// the output for format.Source.
+type Generator struct {
+	Buf bytes.Buffer // Accumulated output.
+	Pkg *Package     // Package we are scanning.
+
+	opts Config // Options of the run; only the code generation ones are used.
+}
+
+// File holds a single parsed file and associated data.
+type File struct {
+	pkg  *Package  // Package to which this file belongs.
+	file *ast.File // Parsed AST.
+	// These fields are reset for each type being generated.
+	typeName string  // Name of the constant type.
+	values   []Value // Accumulator for constant values of that type.
+	err      error   // First error found by the walker, which stops it.
+}
+
+// Package represents a go package
+type Package struct {
+	name     string
+	defs     map[*ast.Ident]types.Object
+	files    []*File
+	fset     *token.FileSet
+	typesPkg *types.Package
+	imports  map[string]bool // Packages referenced by the generated code.
+}
+
+func (p *Package) GetName() string {
+	return p.name
+}
+
+// PrintHeader puts the header, package clause and imports in front of the
+// generated code. It is called after Generate so that the imports needed by
+// message template parameters are known.
+func (g *Generator) PrintHeader(args []string) {
+	body := g.Buf.String()
+	g.Buf.Reset()
+	g.Printf("// Code generated by \"errorer %s\"; DO NOT EDIT.\n", strings.Join(args, " "))
+	g.Printf("\n")
+	g.Printf("package %s", g.Pkg.GetName())
+	g.Printf("\n")
+	g.Printf("import (\n")
+	g.Printf("\t\"fmt\"\n")           // Used by all methods.
+	g.Printf("\t\"encoding/json\"\n") // Used by all methods.
+	paths := make([]string, 0, len(g.Pkg.imports))
+	for path := range g.Pkg.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		g.Printf("\t%q\n", path) // Used by templates and optional methods.
+	}
+	g.Printf(")\n")
+	g.Buf.WriteString(body)
+}
+
+// Printf writes generated code to the buffer
+func (g *Generator) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.Buf, format, args...)
+}
+
+// ParsePackage loads and type-checks the package named by cfg.Files or cfg.Dir.
+func (g *Generator) ParsePackage(cfg Config) error {
+	return g.parsePackage(cfg, nil)
+}
+
+// parsePackage loads the package named by cfg.Files or cfg.Dir with go/packages.
+// If overlay is non-nil, it maps file names to contents used instead of the
+// files on disk, for testing.
+func (g *Generator) parsePackage(cfg Config, overlay map[string][]byte) error {
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo,
+		Tests: cfg.Tests,
+	}
+	if len(cfg.Tags) > 0 {
+		config.BuildFlags = append(config.BuildFlags, "-tags="+strings.Join(cfg.Tags, ","))
+	}
+	if cfg.Mod != "" {
+		config.BuildFlags = append(config.BuildFlags, "-mod="+cfg.Mod)
+	}
+	patterns := cfg.Files
+	if len(patterns) == 0 {
+		// Load the directory as "." so that it is not mistaken for an import path.
+		config.Dir = cfg.Dir
+		patterns = []string{"."}
+	}
+	if overlay != nil {
+		config.Overlay = make(map[string][]byte)
+		for name, text := range overlay {
+			abs, err := filepath.Abs(name)
+			if err != nil {
+				return err
+			}
+			config.Overlay[abs] = text
+		}
+	}
+	pkgs, err := packages.Load(config, patterns...)
+	if err != nil {
+		return fmt.Errorf("loading package: %s", err)
+	}
+	pkg := choosePackage(pkgs, cfg.Tests)
+	if pkg == nil {
+		return fmt.Errorf("%s: no packages found", strings.Join(patterns, " "))
+	}
+	// The package may depend on declarations that only the generated file
+	// provides, such as the Error method or message template constructors.
+	// Type and compile errors are therefore not fatal; the constants we need
+	// are still resolved, and the compiler reports anything genuinely wrong.
+	for _, e := range pkg.Errors {
+		if e.Kind == packages.ParseError {
+			return &Diagnostic{Pos: parsePosition(e.Pos), Msg: e.Msg}
+		}
+	}
+	if len(pkg.Syntax) == 0 {
+		if len(pkg.Errors) > 0 {
+			return &Diagnostic{Pos: parsePosition(pkg.Errors[0].Pos), Msg: pkg.Errors[0].Msg}
+		}
+		return fmt.Errorf("%s: no buildable Go files", strings.Join(patterns, " "))
+	}
+	g.Pkg = NewPackage(pkg.Fset, pkg.Syntax, pkg.Types, pkg.TypesInfo)
+	return nil
+}
+
+// NewPackage returns the Package for files, which have already been parsed
+// and type-checked, as in a go/analysis pass. info must record Defs.
+func NewPackage(fset *token.FileSet, files []*ast.File, pkg *types.Package, info *types.Info) *Package {
+	p := &Package{
+		name:     pkg.Name(),
+		defs:     info.Defs,
+		fset:     fset,
+		typesPkg: pkg,
+		imports:  make(map[string]bool),
+	}
+	for _, file := range files {
+		p.files = append(p.files, &File{
+			file: file,
+			pkg:  p,
+		})
+	}
+	return p
+}
+
+// choosePackage picks the package to generate for from the result of
+// packages.Load. With tests, that is the variant of the package compiled
+// with its _test.go files.
+func choosePackage(pkgs []*packages.Package, tests bool) *packages.Package {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	if tests {
+		for _, pkg := range pkgs {
+			if strings.HasSuffix(pkg.ID, ".test]") && !strings.HasSuffix(pkg.Name, "_test") {
+				return pkg
+			}
+		}
+	}
+	return pkgs[0]
+}
+
+// parsePosition parses a "file:line:col" position as reported by go/packages.
+func parsePosition(s string) token.Position {
+	var pos token.Position
+	parts := strings.Split(s, ":")
+	for len(parts) > 1 {
+		n, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			break
+		}
+		pos.Column, pos.Line = pos.Line, n
+		parts = parts[:len(parts)-1]
+	}
+	pos.Filename = strings.Join(parts, ":")
+	return pos
+}
+
+// methods lists the lookup methods to generate, in output order, with the
+// prefix naming their tables and the Value field each one returns.
+var methods = []struct {
+	name   string
+	prefix string
+	field  func(Value) string
+}{
+	{"String", "name", valueName},
+	{"Error", "msg", valueMsg},
+}
+
+// Generate produces the String method for the named type.
+func (g *Generator) Generate(typeName string) error {
+	values, err := g.collect(typeName)
+	if err != nil {
+		return err
+	}
+
+	runs := splitIntoRuns(values)
+	if err := g.checkNames(runs); err != nil {
+		return err
+	}
+	// String codes go by their value in JSON, text, SQL and ErrorInfo,
+	// integer codes by their name.
+	key, zero := "i.String()", "0"
+	if runs[0][0].isString {
+		if g.opts.SQL == "int" {
+			return fmt.Errorf("cannot store %s as an integer, as it is a string type", typeName)
+		}
+		key, zero = "string(i)", `""`
+	}
+	// The decision of which pattern to use depends on the number of
+	// runs in the numbers. If there's only one, it's easy. For more than
+	// one, there's a tradeoff between complexity and size of the data
+	// and code vs. the simplicity of a map. A map takes more space,
+	// but so does the code. The decision here (crossover at 10) is
+	// arbitrary, but considers that for large numbers of runs the cost
+	// of the linear scan in the switch might become important, and
+	// rather than use yet another algorithm such as binary search,
+	// we punt and use a map. In any case, the likelihood of a map
+	// being necessary for any realistic example other than bitmasks
+	// is very low. And bitmasks probably deserve their own analysis,
+	// to be done some other day.
+	g.buildMethods(runs, typeName)
+	if tags := locales(runs); len(tags) > 0 {
+		g.buildLocalize(runs, typeName, tags)
+	}
+	// RFC 7807 problem details carry the HTTP status.
+	if hasCodeAnnotations(runs) || g.opts.JSON.Shape == "rfc7807" {
+		g.buildCodeMethods(runs, typeName)
+	}
+	if hasDetails(runs) {
+		g.buildLookup(runs, typeName, "detail", "Detail", valueDetail)
+	}
+	if hasTemplates(runs) {
+		g.buildTemplates(runs, typeName)
+	}
+	if g.opts.IgnoreCase {
+		if err := g.buildFoldedLookup(runs, typeName); err != nil {
+			return err
+		}
+	}
+	g.buildErrStrToValueMap(runs, typeName, zero)
+	g.buildValues(runs, typeName)
+	g.buildJsonMethods(typeName, g.opts.JSON, key)
+	if g.opts.Text {
+		g.buildTextMethods(typeName, key)
+	}
+	if g.opts.SQL != "" {
+		g.buildSQLMethods(runs, typeName, g.opts.SQL)
+	}
+	g.buildWrapper(typeName)
+	if g.opts.ProtoDomain != "" {
+		g.buildErrorInfo(runs, typeName, g.opts.ProtoDomain, key, zero)
+	}
+	return nil
+}
+
+func (g *Generator) buildMethods(runs [][]Value, typeName string) {
+	for _, m := range methods {
+		field := m.field
+		if m.prefix == "name" {
+			field = g.name
+		}
+		g.buildLookup(runs, typeName, m.prefix, m.name, field)
+	}
+}
+
+// name returns the name of v as String returns it, after the transform
+// checked by the package-level Generate.
+func (g *Generator) name(v Value) string {
+	transform, _ := transformName(g.opts.Transform)
+	return transform(v.name)
+}
+
+// checkNames reports values whose names String could not tell apart after
+// the transform.
+func (g *Generator) checkNames(runs [][]Value) error {
+	seen := make(map[string]string)
+	for _, run := range runs {
+		for _, v := range run {
+			name := g.name(v)
+			if other, ok := seen[name]; ok {
+				return &Diagnostic{Pos: v.pos, Msg: fmt.Sprintf("%s and %s are both named %q", other, v.name, name)}
+			}
+			seen[name] = v.name
+		}
+	}
+	return nil
+}
+
+// buildLookup generates a method returning field for each value, choosing
+// the layout of its tables from the runs.
+func (g *Generator) buildLookup(runs [][]Value, typeName, prefix, methodName string, field func(Value) string) {
+	switch {
+	case runs[0][0].isString:
+		g.buildSwitch(runs, typeName, methodName, field)
+	case len(runs) == 1:
+		g.buildOneRun(runs, typeName, prefix, methodName, field)
+	case len(runs) <= 10:
+		g.buildMultipleRuns(runs, typeName, prefix, methodName, field)
+	default:
+		g.buildMap(runs, typeName, prefix, methodName, field)
+	}
+}
+
+// collect returns the constants of the named type, in declaration order.
+func (g *Generator) collect(typeName string) ([]Value, error) {
+	values := make([]Value, 0, 100)
+	for _, file := range g.Pkg.files {
+		// Set the state for this run of the walker.
+		file.typeName = typeName
+		file.values = nil
+		if file.file != nil {
+			file.err = nil
+			ast.Inspect(file.file, file.genDecl)
+			if file.err != nil {
+				return nil, file.err
+			}
+			values = append(values, file.values...)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no values defined for type %s", typeName)
+	}
+	return values, nil
+}
+
+// splitIntoRuns breaks the values into runs of contiguous sequences.
+// For example, given 1,2,3,5,6,7 it returns {1,2,3},{5,6,7}.
+// String values each make a run of their own.
+// The input slice is known to be non-empty.
+func splitIntoRuns(values []Value) [][]Value {
+	// We use stable sort so the lexically first name is chosen for equal elements.
+	sort.Stable(byValue(values))
+	// Remove duplicates. Stable sort has put the one we want to print first,
+	// so use that one. The String method won't care about which named constant
+	// was the argument, so the first name for the given value is the only one to keep.
+	// We need to do this because identical values would cause the switch or map
+	// to fail to compile.
+	j := 1
+	for i := 1; i < len(values); i++ {
+		if values[i].str != values[i-1].str {
+			values[j] = values[i]
+			j++
+		}
+	}
+	values = values[:j]
+	runs := make([][]Value, 0, 10)
+	for len(values) > 0 {
+		// One contiguous sequence per outer loop.
+		i := 1
+		for i < len(values) && !values[i].isString && values[i].value == values[i-1].value+1 {
+			i++
+		}
+		runs = append(runs, values[:i])
+		values = values[i:]
+	}
+	return runs
+}
+
+// Format returns the gofmt-ed contents of the Generator's buffer.
+func (g *Generator) Format() ([]byte, error) {
+	src, err := format.Source(g.Buf.Bytes())
+	if err != nil {
+		// Should never happen, but can arise when developing this code.
+		return nil, fmt.Errorf("internal error: invalid Go generated: %s", err)
+	}
+	return src, nil
+}
+
+// Value represents a declared constant.
+type Value struct {
+	name string // The name of the constant.
+	// The value is stored as a bit pattern alone. The boolean tells us
+	// whether to interpret it as an int64 or a uint64; the only place
+	// this matters is when sorting.
+	// Much of the time the str field is all we need; it is printed
+	// by Value.String.
+	value        uint64            // Will be converted to int64 when needed.
+	msg          string            // This is the error message
+	detail       string            // Further explanation returned by Detail, if any.
+	format       string            // fmt format of a message template, if any.
+	params       []Param           // Typed parameters of the message template.
+	annotations  map[string]string // Parsed from //errorer: comment lines.
+	translations map[string]string // Messages in other locales, by normalized tag.
+	signed       bool              // Whether the constant is a signed type.
+	isString     bool              // Whether the constant is a string; value is then unused.
+	str          string            // The string representation given by the "go/exact" package.
+	pos          token.Position    // Where the constant is declared.
+}
+
+func (v *Value) String() string {
+	return v.str
+}
+
+// stringValue returns the value of a string constant, unquoted.
+func (v *Value) stringValue() string {
+	s, _ := strconv.Unquote(v.str)
+	return s
+}
+
+// byValue lets us sort the constants into increasing order.
+// We take care in the Less method to sort in signed or unsigned order,
+// as appropriate, and strings by their values.
+type byValue []Value
+
+func (b byValue) Len() int      { return len(b) }
+func (b byValue) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byValue) Less(i, j int) bool {
+	if b[i].isString {
+		return b[i].stringValue() < b[j].stringValue()
+	}
+	if b[i].signed {
+		return int64(b[i].value) < int64(b[j].value)
+	}
+	return b[i].value < b[j].value
+}
+
+// genDecl processes one declaration clause.
+func (f *File) genDecl(node ast.Node) bool {
+	if f.err != nil {
+		return false
+	}
+	decl, ok := node.(*ast.GenDecl)
+	if !ok || decl.Tok != token.CONST {
+		// We only care about const declarations.
+		return true
+	}
+
+	// The name of the type of the constants we are declaring.
+	// Can change if this is a multi-element declaration.
+	typ := ""
+	// Loop over the elements of the declaration. Each element is a ValueSpec:
+	// a list of names possibly followed by a type, possibly followed by values.
+	// If the type and value are both missing, we carry down the type (and value,
+	// but the "go/types" package takes care of that).
+	for _, spec := range decl.Specs {
+		vspec := spec.(*ast.ValueSpec) // Guaranteed to succeed as this is CONST.
+		if vspec.Type == nil && len(vspec.Values) > 0 {
+			// "X = 1". With no type but a value, the constant is untyped.
+			// Skip this vspec and reset the remembered type.
+			typ = ""
+			continue
+		}
+		if vspec.Type != nil {
+			// "X T". We have a type. Remember it.
+			ident, ok := vspec.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			typ = ident.Name
+		}
+		if typ != f.typeName {
+			// This is not the type we're looking for.
+			continue
+		}
+		// We now have a list of names (from one line of source code) all being
+		// declared with the desired type.
+		// Grab their names and actual values and store them in f.values.
+		for _, name := range vspec.Names {
+			if name.Name == "_" {
+				continue
+			}
+			// This dance lets the type checker find the values for us. It's a
+			// bit tricky: look up the object declared by the name, find its
+			// types.Const, and extract its value.
+			obj, ok := f.pkg.defs[name]
+			if !ok {
+				return f.errorf(name, "no value for constant %s", name)
+			}
+			info := obj.Type().Underlying().(*types.Basic).Info()
+			if info&(types.IsInteger|types.IsString) == 0 {
+				return f.errorf(name, "can't handle constant type %s, which is neither integer nor string", typ)
+			}
+			value := obj.(*types.Const).Val() // Guaranteed to succeed as this is CONST.
+			var u64 uint64
+			if info&types.IsString != 0 {
+				if value.Kind() != exact.String {
+					return f.errorf(name, "can't happen: constant is not a string %s", name)
+				}
+			} else {
+				if value.Kind() != exact.Int {
+					return f.errorf(name, "can't happen: constant is not an integer %s", name)
+				}
+				i64, isInt := exact.Int64Val(value)
+				var isUint bool
+				u64, isUint = exact.Uint64Val(value)
+				if !isInt && !isUint {
+					return f.errorf(name, "internal error: value of %s is not an integer: %s", name, value.String())
+				}
+
+				if !isInt {
+					u64 = uint64(i64)
+				}
+			}
+			msg, detail, annotations, err := constantComment(decl, vspec)
+			if err != nil {
+				return f.errorf(name, "bad annotation for constant %s: %s", name, err)
+			}
+			msg, format, params, err := f.parseTemplate(msg, name.Pos())
+			if err != nil {
+				return f.errorf(name, "bad message template for constant %s: %s", name, err)
+			}
+			// Details are not templates, but read like the plain
+			// message, with {name:type} reduced to {name}.
+			v := Value{
+				name:        name.Name,
+				msg:         msg,
+				detail:      placeholder.ReplaceAllString(detail, "{$1}"),
+				format:      format,
+				params:      params,
+				annotations: annotations,
+				value:       u64,
+				signed:      info&types.IsUnsigned == 0,
+				isString:    info&types.IsString != 0,
+				str:         value.ExactString(),
+				pos:         f.pkg.fset.Position(name.Pos()),
+			}
+			if err := checkCodeAnnotations(&v); err != nil {
+				return f.errorf(name, "bad annotation: %s", err)
+			}
+			if err := parseTranslations(&v); err != nil {
+				return f.errorf(name, "bad annotation: %s", err)
+			}
+			f.values = append(f.values, v)
+		}
+	}
+	return false
+}
+
+// errorf records a Diagnostic at node as the walker's error and stops the walk.
+func (f *File) errorf(node ast.Node, format string, args ...interface{}) bool {
+	f.err = &Diagnostic{
+		Pos: f.pkg.fset.Position(node.Pos()),
+		Msg: fmt.Sprintf(format, args...),
+	}
+	return false
+}
+
+// Helpers
+
+// usize returns the number of bits of the smallest unsigned integer
+// type that will hold n. Used to create the smallest possible slice of
+// integers to use as indexes into the concatenated strings.
+func usize(n int) int {
+	switch {
+	case n < 1<<8:
+		return 8
+	case n < 1<<16:
+		return 16
+	default:
+		// 2^32 is enough constants for anyone.
+		return 32
+	}
+}
+
+// declareIndexAndNameVars declares the index slices and concatenated names
+// strings representing the runs of values.
+func (g *Generator) declareIndexAndNameVars(runs [][]Value, typeName string, prefix string, field func(Value) string) {
+	var indexes, names []string
+	for i, run := range runs {
+		index, name := g.createIndexAndNameDecl(run, typeName, prefix, fmt.Sprintf("_%d", i), field)
+		indexes = append(indexes, index)
+		names = append(names, name)
+	}
+	g.Printf("const (\n")
+	for _, name := range names {
+		g.Printf("\t%s\n", name)
+	}
+	g.Printf(")\n\n")
+	g.Printf("var (")
+	for _, index := range indexes {
+		g.Printf("\t%s\n", index)
+	}
+	g.Printf(")\n\n")
+}
+
+// declareIndexAndNameVar is the single-run version of declareIndexAndNameVars
+func (g *Generator) declareIndexAndNameVar(run []Value, typeName string, prefix string, field func(Value) string) {
+	index, name := g.createIndexAndNameDecl(run, typeName, prefix, "", field)
+	g.Printf("const %s\n", name)
+	g.Printf("var %s\n", index)
+}
+
+// createIndexAndNameDecl returns the pair of declarations for the run, holding
+// field of each value. The caller will add "const" and "var".
+func (g *Generator) createIndexAndNameDecl(run []Value, typeName string, prefix string, suffix string, field func(Value) string) (string, string) {
+	b := new(bytes.Buffer)
+	indexes := make([]int, len(run))
+	for i := range run {
+		b.WriteString(field(run[i]))
+		indexes[i] = b.Len()
+	}
+	nameConst := fmt.Sprintf("_%s_%s%s = %q", typeName, prefix, suffix, b.String())
+	nameLen := b.Len()
+	b.Reset()
+	fmt.Fprintf(b, "_%s_%s_index%s = [...]uint%d{0, ", typeName, prefix, suffix, usize(nameLen))
+	for i, v := range indexes {
+		if i > 0 {
+			fmt.Fprintf(b, ", ")
+		}
+		fmt.Fprintf(b, "%d", v)
+	}
+	fmt.Fprintf(b, "}")
+	return b.String(), nameConst
+}
+
+// valueName returns the name of v.
+func valueName(v Value) string {
+	return v.name
+}
+
+// valueDetail returns the detail of v.
+func valueDetail(v Value) string {
+	return v.detail
+}
+
+// valueMsg returns the message of v, without the trailing newline left by
+// comment text.
+func valueMsg(v Value) string {
+	return strings.TrimSuffix(v.msg, "\n")
+}
+
+// declareNameVars declares the concatenated field strings, names or messages,
+// representing all the values in the runs.
+func (g *Generator) declareNameVars(runs [][]Value, typeName string, prefix string, field func(Value) string) {
+	b := new(bytes.Buffer)
+	for _, run := range runs {
+		for i := range run {
+			b.WriteString(field(run[i]))
+		}
+	}
+	g.Printf("const _%s_%s = %q\n", typeName, prefix, b.String())
+}
+
+// buildOneRun generates the variables and String method for a single run of contiguous values.
+func (g *Generator) buildOneRun(runs [][]Value, typeName string, prefix string, methodName string, field func(Value) string) {
+	values := runs[0]
+	g.Printf("\n")
+	g.declareIndexAndNameVar(values, typeName, prefix, field)
+	// The generated code is simple enough to write as a Printf format.
+	lessThanZero := ""
+	if values[0].signed {
+		lessThanZero = "i < 0 || "
+	}
+	if values[0].value == 0 { // Signed or unsigned, 0 is still 0.
+		g.Printf(stringOneRun, typeName, methodName, prefix, usize(len(values)), lessThanZero)
+	} else {
+		g.Printf(stringOneRunWithOffset, typeName, methodName, prefix, values[0].String(), usize(len(values)), lessThanZero)
+	}
+}
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: method name
+//	[3]: prefix
+//	[4]: size of index element (8 for uint8 etc.)
+//	[5]: less than zero check (for signed types)
+const stringOneRun = `func (i %[1]s) %[2]s() string {
+	if %[5]si >= %[1]s(len(_%[1]s_%[3]s_index)-1) {
+		return fmt.Sprintf("%[1]s(%%d)", i)
+	}
+	return _%[1]s_%[3]s[_%[1]s_%[3]s_index[i]:_%[1]s_%[3]s_index[i+1]]
+}
+`
+
+// Arguments to format are:
+//	[1]: type name
+//	[2]: method name
+//	[3]: prefix
+//	[4]: lowest defined value for type, as a string
+//	[5]: size of index element (8 for uint8 etc.)
+//	[6]: less than zero check (for signed types)
+const stringOneRunWithOffset = `func (i %[1]s) %[2]s() string {
+	i -= %[4]s
+	if %[6]si >= %[1]s(len(_%[1]s_%[3]s_index)-1) {
+		return fmt.Sprintf("%[1]s(%%d)", i + %[4]s)
+	}
+	return _%[1]s_%[3]s[_%[1]s_%[3]s_index[i] : _%[1]s_%[3]s_index[i+1]]
+}
+`
+
+// buildMultipleRuns generates the variables and String method for multiple runs of contiguous values.
+// For this pattern, a single Printf format won't do.
+func (g *Generator) buildMultipleRuns(runs [][]Value, typeName string, prefix string, methodName string, field func(Value) string) {
+	g.Printf("\n")
+	g.declareIndexAndNameVars(runs, typeName, prefix, field)
+	g.Printf("func (i %s) %s() string {\n", typeName, methodName)
+	g.Printf("\tswitch {\n")
+	for i, values := range runs {
+		if len(values) == 1 {
+			g.Printf("\tcase i == %s:\n", &values[0])
+			g.Printf("\t\treturn _%s_%s_%d\n", typeName, prefix, i)
+			continue
+		}
+		g.Printf("\tcase %s <= i && i <= %s:\n", &values[0], &values[len(values)-1])
+		if values[0].value != 0 {
+			g.Printf("\t\ti -= %s\n", &values[0])
+		}
+		g.Printf("\t\treturn _%s_%s_%d[_%s_%s_index_%d[i]:_%s_%s_index_%d[i+1]]\n",
+			typeName, prefix, i, typeName, prefix, i, typeName, prefix, i)
+	}
+	g.Printf("\tdefault:\n")
+	g.Printf("\t\treturn fmt.Sprintf(\"%s(%%d)\", i)\n", typeName)
+	g.Printf("\t}\n")
+	g.Printf("}\n")
+}
+
+// buildMap handles the case where the space is so sparse a map is a reasonable fallback.
+// It's a rare situation but has simple code.
+func (g *Generator) buildMap(runs [][]Value, typeName string, prefix string, methodName string, field func(Value) string) {
+	g.Printf("\n")
+	g.declareNameVars(runs, typeName, prefix, field)
+	g.Printf("\nvar _%[1]s_%[2]s_map = map[%[1]s]string{\n", typeName, prefix)
+	n := 0
+	for _, values := range runs {
+		for _, value := range values {
+			size := len(field(value))
+			g.Printf("\t%s: _%s_%s[%d:%d],\n", &value, typeName, prefix, n, n+size)
+			n += size
+		}
+	}
+	g.Printf("}\n\n")
+	g.Printf(stringMap, typeName, prefix, methodName)
+}
+
+// buildSwitch generates a method returning field for each value of a
+// string type, which has no runs to index tables by.
+func (g *Generator) buildSwitch(runs [][]Value, typeName string, methodName string, field func(Value) string) {
+	g.Printf("\nfunc (i %s) %s() string {\n", typeName, methodName)
+	g.Printf("\tswitch i {\n")
+	for _, values := range runs {
+		for _, value := range values {
+			g.Printf("\tcase %s:\n", value.name)
+			g.Printf("\t\treturn %q\n", field(value))
+		}
+	}
+	g.Printf("\t}\n")
+	g.Printf("\treturn fmt.Sprintf(\"%s(%%q)\", string(i))\n", typeName)
+	g.Printf("}\n")
+}
+
+// Arguments to format
+// [1] typeName
+// [2] prefix
+// [3] methodName
+const stringMap = `func (i %[1]s) %[3]s() string {
+	if str, ok := _%[1]s_%[2]s_map[i]; ok {
+		return str
+	}
+	return fmt.Sprintf("%[1]s(%%d)", i)
+}`
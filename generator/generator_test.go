@@ -0,0 +1,816 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Simple, no offset, single-run enum of errors
+var basic_in = `type Error int
+const (
+	NotFound         Error = iota //User could not be found
+	AlreadyExists                 //User already exists
+	NotSure                       //Not sure what happened
+	BadRequestData                //You didn't send a good request
+	WorksOnMyMachine              //Works on my machine
+)
+`
+
+const offset_in = `type Error int
+const (
+	NotFound         Error = iota + 100 //User could not be found
+	AlreadyExists						//User already exists
+	NotSure								//Not sure what happened
+	BadRequestData						//You didn't send a good request
+	WorksOnMyMachine					//Works on my machine
+)
+`
+
+const multiple_in = `type Error int
+const (
+	NotFound         Error = 100		//User could not be found
+	AlreadyExists	 Error = 101				//User already exists
+	NotSure			 Error = 103					//Not sure what happened
+	BadRequestData	Error = 104				//You didn't send a good request
+	WorksOnMyMachine Error = 105					//Works on my machine
+)
+`
+
+// parseSource parses src as the only file of a package, without touching the disk.
+func parseSource(g *Generator, name, src string) error {
+	return g.parsePackage(Config{Files: []string{name}}, map[string][]byte{name: []byte(src)})
+}
+
+// A map of more than ten runs, where String and Error fall back to maps.
+const map_in = `type Error int
+const (
+	BadRequest          Error = 400 //Bad request
+	Unauthorized        Error = 402 //Unauthorized
+	NotFound            Error = 404 //Not found
+	Conflict            Error = 409 //Conflict
+	PreconditionFailed  Error = 412 //Precondition failed
+	UnsupportedMedia    Error = 415 //Unsupported media type
+	Teapot              Error = 418 //I'm a teapot
+	Unprocessable       Error = 422 //Unprocessable entity
+	TooManyRequests     Error = 429 //Too many requests
+	Internal            Error = 500 //Internal error
+	Unavailable         Error = 503 //Service unavailable
+)
+`
+
+// String codes, whose methods switch on the value.
+const string_in = `type Code string
+const (
+	UserNotFound Code = "user.not_found" //User could not be found
+	UserExists   Code = "user.exists"    //User already exists
+	Internal     Code = "internal"       //Internal error
+	Unknown      Code = "internal"       //Alias of Internal
+)
+`
+
+var update = flag.Bool("update", false, "update the golden files in fixtures/golden")
+
+type Golden struct {
+	name  string
+	input string
+}
+
+var golden = []Golden{
+	{"basic", basic_in},
+	{"offset", offset_in},
+	{"multiple", multiple_in},
+	{"map", map_in},
+	{"string", string_in},
+}
+
+// TestGolden compares the complete generated file for each input with
+// fixtures/golden/<name>.golden. Run with -update to rewrite the golden files.
+func TestGolden(t *testing.T) {
+	for _, test := range golden {
+		var g Generator
+		in := "package test\n" + test.input
+		file := test.name + ".go"
+		if err := parseSource(&g, file, in); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		tokens := strings.SplitN(test.input, " ", 3)
+
+		if len(tokens) != 3 {
+			t.Fatalf("Need type declaration on first line")
+		}
+
+		if err := g.Generate(tokens[1]); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		g.PrintHeader([]string{"-type=" + tokens[1]})
+
+		src, err := g.Format()
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		checkGolden(t, test.name+".golden", src)
+	}
+}
+
+// checkGolden compares got with the golden file fixtures/golden/<name>, or
+// rewrites the golden file when run with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	goldenFile := filepath.Join("..", "fixtures", "golden", name)
+	if *update {
+		if err := ioutil.WriteFile(goldenFile, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s: got\n====\n%s====\nexpected\n====\n%s", name, got, want)
+	}
+}
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		in   string
+		want map[string]string
+		err  bool
+	}{
+		{`http=404 grpc=NotFound`, map[string]string{"http": "404", "grpc": "NotFound"}, false},
+		{`msg="User could not be found" http=404`, map[string]string{"msg": "User could not be found", "http": "404"}, false},
+		{`msg="tab\tand \"quote\""`, map[string]string{"msg": "tab\tand \"quote\""}, false},
+		{`deprecated  code=7`, map[string]string{"deprecated": "", "code": "7"}, false},
+		{`msg="unterminated`, nil, true},
+		{`=404`, nil, true},
+	}
+	for _, test := range tests {
+		got := make(map[string]string)
+		err := parseAnnotations(test.in, got)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error %v", test.in, err)
+			continue
+		}
+		if !test.err && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, expected %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		typ   string
+		pos   string
+		msg   string
+	}{
+		{"annotation", `type Error int
+const (
+	NotFound Error = iota //errorer:http=four
+)
+`, "Error", "annotation.go:4:2", "bad annotation: NotFound: http=\"four\" is not an integer"},
+		{"template", `type Error int
+const (
+	NotFound Error = iota // User {id:nosuchtype} could not be found
+)
+`, "Error", "template.go:4:2", "bad message template for constant NotFound: parameter id: undefined: nosuchtype"},
+		{"keyword", `type Error int
+const (
+	NotFound Error = iota // User {type:string} not found
+)
+`, "Error", "keyword.go:4:2", "bad message template for constant NotFound: parameter name type is not a usable Go identifier"},
+		{"blank", `type Error int
+const (
+	NotFound Error = iota // User {_:string} not found
+)
+`, "Error", "blank.go:4:2", "bad message template for constant NotFound: parameter name _ is not a usable Go identifier"},
+		{"locale", `type Error int
+const (
+	NotFound Error = iota //errorer:msg.d!e=Nein
+)
+`, "Error", "locale.go:4:2", "bad annotation: NotFound: \"d!e\" is not a language tag"},
+		{"syntax", `type Error int
+const (
+	NotFound Error = iota +
+)
+`, "Error", "syntax.go:5:1", "expected operand, found ')'"},
+		{"float", `type Error float64
+const (
+	NotFound Error = 1.5 // Not found
+)
+`, "Error", "float.go:4:2", "can't handle constant type Error, which is neither integer nor string"},
+	}
+	for _, test := range tests {
+		var g Generator
+		err := parseSource(&g, test.name+".go", "package test\n"+test.input)
+		if err == nil {
+			err = g.Generate(test.typ)
+		}
+		diag, ok := err.(*Diagnostic)
+		if !ok {
+			t.Errorf("%s: expected a *Diagnostic, got %v", test.name, err)
+			continue
+		}
+		pos := fmt.Sprintf("%s:%d:%d", filepath.Base(diag.Pos.Filename), diag.Pos.Line, diag.Pos.Column)
+		if pos != test.pos || diag.Msg != test.msg {
+			t.Errorf("%s: got %s: %s, expected %s: %s", test.name, pos, diag.Msg, test.pos, test.msg)
+		}
+	}
+}
+
+func TestGenerateNoValues(t *testing.T) {
+	var g Generator
+	if err := parseSource(&g, "empty.go", "package test\ntype Error int\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Generate("Error"); err == nil || err.Error() != "no values defined for type Error" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/codes\n",
+		"codes.go": `package codes
+
+type Error int
+
+const NotFound Error = 1 // Not found
+`,
+		"tagged.go": `//go:build special
+
+package codes
+
+type Special int
+
+const Gone Special = 1 // Gone
+`,
+		"codes_test.go": `package codes
+
+type TestOnly int
+
+const Flaky TestOnly = 1 // Flaky
+`,
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		cfg Config
+		ok  bool
+	}{
+		{Config{Types: []string{"Error"}}, true},
+		{Config{Types: []string{"Special"}}, false},
+		{Config{Types: []string{"Special"}, Tags: []string{"special"}}, true},
+		{Config{Types: []string{"TestOnly"}}, false},
+		{Config{Types: []string{"TestOnly"}, Tests: true}, true},
+		{Config{Types: []string{"Error", "TestOnly"}, Tests: true}, true},
+	}
+	for _, test := range tests {
+		test.cfg.Dir = dir
+		_, err := Generate(test.cfg)
+		if (err == nil) != test.ok {
+			t.Errorf("%+v: unexpected error %v", test.cfg, err)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	var g Generator
+	src := `package test
+type Error int
+const (
+	NotFound Error = iota //errorer:http=404 msg="User could not be found"
+	Missing  Error = 0    // Alias
+	Quoted   Error = 7    // Say "hi"
+)
+`
+	if err := parseSource(&g, "catalog.go", src); err != nil {
+		t.Fatal(err)
+	}
+	values, err := g.collect("Error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enums := []Enum{newEnum("Error", values)}
+
+	var b bytes.Buffer
+	if err := WriteCatalog(&b, enums, "json"); err != nil {
+		t.Fatal(err)
+	}
+	const wantJSON = `[
+  {
+    "type": "Error",
+    "constants": [
+      {
+        "name": "NotFound",
+        "value": 0,
+        "message": "User could not be found",
+        "annotations": {
+          "http": "404",
+          "msg": "User could not be found"
+        },
+        "file": "catalog.go",
+        "line": 4
+      },
+      {
+        "name": "Missing",
+        "value": 0,
+        "message": "Alias",
+        "file": "catalog.go",
+        "line": 5
+      },
+      {
+        "name": "Quoted",
+        "value": 7,
+        "message": "Say \"hi\"",
+        "file": "catalog.go",
+        "line": 6
+      }
+    ]
+  }
+]
+`
+	if b.String() != wantJSON {
+		t.Errorf("json: got\n%s\nexpected\n%s", b.String(), wantJSON)
+	}
+
+	b.Reset()
+	if err := WriteCatalog(&b, enums, CatalogFormat("errors.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	const wantYAML = `- type: "Error"
+  constants:
+    - name: "NotFound"
+      value: 0
+      message: "User could not be found"
+      annotations:
+        "http": "404"
+        "msg": "User could not be found"
+      file: "catalog.go"
+      line: 4
+    - name: "Missing"
+      value: 0
+      message: "Alias"
+      file: "catalog.go"
+      line: 5
+    - name: "Quoted"
+      value: 7
+      message: "Say \"hi\""
+      file: "catalog.go"
+      line: 6
+`
+	if b.String() != wantYAML {
+		t.Errorf("yaml: got\n%s\nexpected\n%s", b.String(), wantYAML)
+	}
+}
+
+// mapEnums describes the map golden input, plus an alias that must not
+// appear as a separate name in outputs describing the JSON envelope.
+func mapEnums(t *testing.T) []Enum {
+	var g Generator
+	if err := parseSource(&g, "schema.go", "package test\n"+map_in+"const Alias Error = 404 //Alias of NotFound\n"); err != nil {
+		t.Fatal(err)
+	}
+	values, err := g.collect("Error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []Enum{newEnum("Error", values)}
+}
+
+// TestSchema compares the schemas for the map golden input with
+// fixtures/golden/<format>.json.golden. Run with -update to rewrite them.
+func TestSchema(t *testing.T) {
+	enums := mapEnums(t)
+	for _, format := range []string{"jsonschema", "openapi"} {
+		var b bytes.Buffer
+		if err := WriteSchema(&b, enums, format, Envelope{}); err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, format+".json.golden", b.Bytes())
+	}
+
+	var b bytes.Buffer
+	if err := WriteSchema(&b, enums, "jsonschema", Envelope{Shape: "rfc7807"}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "rfc7807.json.golden", b.Bytes())
+}
+
+func TestTypeScript(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteTypeScript(&b, mapEnums(t), Envelope{}, []string{"-type=Error", "-ts-out=errors.ts"}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "typescript.ts.golden", b.Bytes())
+
+	b.Reset()
+	env := Envelope{Shape: "rfc7807", Fields: map[string]string{"code": "error-code"}}
+	if err := WriteTypeScript(&b, mapEnums(t), env, []string{"-type=Error", "-json=rfc7807", "-json-fields=code:error-code"}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "rfc7807.ts.golden", b.Bytes())
+}
+
+func TestEnvelope(t *testing.T) {
+	fields, err := ParseEnvelopeFields("code:error, msg:detail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"code": "error", "msg": "detail"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("got fields %v, expected %v", fields, want)
+	}
+	if _, err := ParseEnvelopeFields("code"); err == nil {
+		t.Error("no error for a field without a key")
+	}
+
+	tests := []struct {
+		env Envelope
+		err string
+	}{
+		{Envelope{Shape: "xml"}, `unknown JSON shape "xml"`},
+		{Envelope{Fields: map[string]string{"status": "code"}}, `unknown JSON field "status"; want code or msg`},
+		{Envelope{Fields: map[string]string{"code": `a"b`}}, `bad JSON key "a\"b" for code`},
+		{Envelope{Fields: map[string]string{"msg": "type"}}, `JSON key "type" is used twice`},
+		{Envelope{Shape: "rfc7807", Fields: map[string]string{"code": "status"}}, `JSON key "status" is used twice`},
+	}
+	for _, test := range tests {
+		if _, err := test.env.members(); err == nil || err.Error() != test.err {
+			t.Errorf("%+v: got error %v, expected %s", test.env, err, test.err)
+		}
+	}
+}
+
+func TestProto(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteProto(&b, mapEnums(t), "example.errors", []string{"-type=Error", "-proto=errors.proto"}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "proto.proto.golden", b.Bytes())
+
+	// The zero value must come first, wherever it is declared.
+	var g Generator
+	src := `package test
+type Error int
+const (
+	Minus    Error = iota - 2 // Minus two
+	MinusOne                  // Minus one
+	OK                        // OK
+	Created                   // Created
+	Zero     Error = 0        // Alias of OK
+)
+`
+	if err := parseSource(&g, "signed.go", src); err != nil {
+		t.Fatal(err)
+	}
+	e, err := g.Enum("Error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if err := WriteProto(&b, []Enum{e}, "", []string{"-type=Error", "-proto=errors.proto"}); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "signed.proto.golden", b.Bytes())
+
+	big := Enum{Type: "Error", Constants: []Constant{{Name: "Huge", Value: "4294967296"}}}
+	if err := WriteProto(&b, []Enum{big}, "", nil); err == nil {
+		t.Error("no error for a value outside the int32 range")
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		in                            string
+		snake, kebab, upperSnake, cml string
+	}{
+		{"NotFound", "not_found", "not-found", "NOT_FOUND", "notFound"},
+		{"HTTPNotFound", "http_not_found", "http-not-found", "HTTP_NOT_FOUND", "httpNotFound"},
+		{"Status404Gone", "status404_gone", "status404-gone", "STATUS404_GONE", "status404Gone"},
+		{"ID", "id", "id", "ID", "id"},
+		{"already_snake", "already_snake", "already-snake", "ALREADY_SNAKE", "alreadysnake"},
+	}
+	for _, test := range tests {
+		for name, want := range map[string]string{
+			"snake":       test.snake,
+			"kebab":       test.kebab,
+			"upper-snake": test.upperSnake,
+			"camel":       test.cml,
+		} {
+			if got := transforms[name](test.in); got != want {
+				t.Errorf("%s(%q) = %q, want %q", name, test.in, got, want)
+			}
+		}
+	}
+	if _, err := transformName("title"); err == nil {
+		t.Error("no error for an unknown transform")
+	}
+}
+
+func TestNameCollisions(t *testing.T) {
+	src := `package test
+type Error int
+const (
+	HTTPError Error = iota // One
+	HttpError              // Two
+)
+`
+	for _, test := range []struct {
+		cfg Config
+		msg string
+	}{
+		{Config{Transform: "snake"}, `HTTPError and HttpError are both named "http_error"`},
+		{Config{IgnoreCase: true}, "HTTPError and HttpError are the same name ignoring case"},
+	} {
+		g := Generator{opts: test.cfg}
+		if err := parseSource(&g, "names.go", src); err != nil {
+			t.Fatal(err)
+		}
+		err := g.Generate("Error")
+		diag, ok := err.(*Diagnostic)
+		if !ok || diag.Msg != test.msg || diag.Pos.Line != 5 {
+			t.Errorf("%+v: got %v, expected a diagnostic on line 5: %s", test.cfg, err, test.msg)
+		}
+	}
+}
+
+func TestMissingTranslations(t *testing.T) {
+	var g Generator
+	src := `package test
+type Error int
+const (
+	NotFound Error = iota //errorer:msg="Not found" msg.de="Nicht gefunden" msg.pt_BR="Não encontrado"
+	Alias    Error = 0    // Alias of NotFound, which is translated
+	Conflict Error = 1    //errorer:msg="Conflict" msg.pt-br="Conflito"
+	Internal Error = 2    // Internal error
+)
+`
+	if err := parseSource(&g, "i18n.go", src); err != nil {
+		t.Fatal(err)
+	}
+	values, err := g.collect("Error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range MissingTranslations([]Enum{newEnum("Error", values)}) {
+		got = append(got, d.Error())
+	}
+	want := []string{
+		"i18n.go:6: Error.Conflict has no de message",
+		"i18n.go:7: Error.Internal has no de message",
+		"i18n.go:7: Error.Internal has no pt-br message",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, expected %q", got, want)
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	tests := []struct {
+		cfg Config
+		err string
+	}{
+		{Config{Types: []string{"Error"}, SQL: "blob"}, `unknown SQL mode "blob"; want name or int`},
+		{Config{Types: []string{"Error"}, JSON: Envelope{Shape: "xml"}}, `unknown JSON shape "xml"`},
+	}
+	for _, test := range tests {
+		if _, err := Generate(test.cfg); err == nil || err.Error() != test.err {
+			t.Errorf("got error %v, expected %s", err, test.err)
+		}
+	}
+}
+
+func TestSplitSentence(t *testing.T) {
+	for _, test := range []struct{ in, msg, detail string }{
+		{"Not found", "Not found", ""},
+		{"Not found.", "Not found", ""},
+		{"Not found. Check the ID. Then retry.", "Not found", "Check the ID. Then retry."},
+		{"Version 1.2 is gone", "Version 1.2 is gone", ""},
+	} {
+		msg, detail := splitSentence(test.in)
+		if msg != test.msg || detail != test.detail {
+			t.Errorf("splitSentence(%q) = %q, %q, want %q, %q", test.in, msg, detail, test.msg, test.detail)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	src := `package test
+
+//go:generate errorer -type=Error,Status -output=errors.go
+//go:generate go run github.com/iantanwx/errorer@v1.2.0 -text -type Reason
+//go:generate stringer -type=Other
+//go:generate errorer -output=missing_type.go
+// go:generate errorer -type=NotADirective
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "gen.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	for _, d := range Directives([]*ast.File{file}) {
+		got = append(got, d.Types)
+	}
+	want := [][]string{{"Error", "Status"}, {"Reason"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got types %q, expected %q", got, want)
+	}
+}
+
+func TestLintCase(t *testing.T) {
+	var g Generator
+	src := `package test
+type Error int
+const (
+	NotFound Error = iota // User not found
+	Timeout               // HTTP request timed out
+	Internal              // internal error
+)
+`
+	if err := parseSource(&g, "case.go", src); err != nil {
+		t.Fatal(err)
+	}
+	for msgCase, want := range map[string][]string{
+		"upper": {`message of Internal should start with an upper-case letter: "internal error"`},
+		"lower": {`message of NotFound should start with a lower-case letter: "User not found"`},
+		"any":   nil,
+	} {
+		diags, err := g.Lint("Error", msgCase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, d := range diags {
+			got = append(got, d.Msg)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, expected %q", msgCase, got, want)
+		}
+	}
+	if _, err := g.Lint("Error", "title"); err == nil {
+		t.Error("no error for an unknown case")
+	}
+}
+
+func TestLock(t *testing.T) {
+	var g Generator
+	src := `package test
+type Error int
+const (
+	NotFound Error = iota // Not found
+	Timeout               // Timeout
+	Conflict              // Conflict
+	Missing  Error = 0    // Alias of NotFound
+)
+`
+	if err := parseSource(&g, "lock.go", src); err != nil {
+		t.Fatal(err)
+	}
+	e, err := g.Enum("Error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := `# A comment.
+
+Other.A 1
+Error.NotFound 0
+Error.Conflict 1
+Error.Gone 3
+`
+	locked, err := ParseLock(strings.NewReader(lock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range CheckLock(locked, []Enum{e}) {
+		got = append(got, d.Error())
+	}
+	want := []string{
+		"lock.go:6: Error.Conflict changed from 1 to 2",
+		"Error.Gone = 3 was removed",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, expected %q", got, want)
+	}
+
+	b := new(bytes.Buffer)
+	if err := WriteLock(b, []Enum{e}, locked); err != nil {
+		t.Fatal(err)
+	}
+	wantLock := lockHeader + `
+Other.A 1
+
+Error.NotFound 0
+Error.Timeout 1
+Error.Conflict 2
+Error.Missing 0
+`
+	if b.String() != wantLock {
+		t.Errorf("got lock file\n%s\nexpected\n%s", b, wantLock)
+	}
+	if diags := CheckLock(mustParseLock(t, b.String()), []Enum{e}); len(diags) != 0 {
+		t.Errorf("written lock file does not match: %v", diags)
+	}
+
+	strs := mustParseLock(t, "Code.Spaced  \"a b\"\n")
+	if len(strs) != 1 || strs[0] != (LockEntry{"Code", "Spaced", `"a b"`}) {
+		t.Errorf("got %q for a string code", strs)
+	}
+	for _, bad := range []string{"Error.NotFound", "NotFound 0", "Error. 0", "Error.NotFound 0 1", "Error.NotFound unquoted"} {
+		if _, err := ParseLock(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseLock(%q) succeeded", bad)
+		}
+	}
+}
+
+func mustParseLock(t *testing.T, s string) []LockEntry {
+	t.Helper()
+	locked, err := ParseLock(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return locked
+}
+
+func TestStringCodes(t *testing.T) {
+	var g Generator
+	src := `package test
+type Code string
+const (
+	NotFound Code = "not found"  // Not found
+	Conflict Code = "conflict"   // Conflict
+)
+`
+	if err := parseSource(&g, "code.go", src); err != nil {
+		t.Fatal(err)
+	}
+	e, err := g.Enum("Code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := WriteCatalog(&b, []Enum{e}, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"value": "not found",`) {
+		t.Errorf("catalog does not hold the value:\n%s", b.String())
+	}
+	b.Reset()
+	if err := WriteTypeScript(&b, []Enum{e}, Envelope{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "  NotFound: \"not found\",\n") || strings.Contains(b.String(), "CodeValues") {
+		t.Errorf("TypeScript does not use the values as names:\n%s", b.String())
+	}
+	err = WriteProto(&b, []Enum{e}, "", nil)
+	if err == nil || err.Error() != "Code is a string type, which has no proto enum" {
+		t.Errorf("got proto error %v", err)
+	}
+	g.opts.SQL = "int"
+	err = g.Generate("Code")
+	if err == nil || err.Error() != "cannot store Code as an integer, as it is a string type" {
+		t.Errorf("got SQL error %v", err)
+	}
+
+	// Code needs a code annotation on every constant of a string type;
+	// testdata/strnumbered.go compiles and calls it.
+	for _, test := range []struct {
+		conflict string
+		want     bool
+	}{
+		{"//errorer:http=409", false},
+		{"//errorer:http=409 code=2", true},
+	} {
+		var g Generator
+		src := "package test\ntype Code string\nconst (\n" +
+			"\tNotFound Code = \"not found\" //errorer:code=1\n" +
+			"\tConflict Code = \"conflict\" " + test.conflict + "\n)\n"
+		if err := parseSource(&g, "code.go", src); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Generate("Code"); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(g.Buf.String(), "func (i Code) Code() int"); got != test.want {
+			t.Errorf("%s: Code generated is %v, want %v", test.conflict, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// localePrefix starts the annotations giving a constant's message in
+// another locale, e.g.
+//
+//	NotFound Error = iota //errorer:msg="User not found" msg.de="Benutzer nicht gefunden"
+const localePrefix = "msg."
+
+// normalizeTag puts a BCP 47 language tag in the form used as a table key,
+// so that "pt_BR", "pt-BR" and "pt-br" are the same locale.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Replace(tag, "_", "-", -1))
+}
+
+// parseTranslations collects the msg.<tag> annotations of v. Typed
+// placeholders are reduced to their names, as in the plain Error message.
+func parseTranslations(v *Value) error {
+	for key, msg := range v.annotations {
+		if !strings.HasPrefix(key, localePrefix) {
+			continue
+		}
+		tag := normalizeTag(key[len(localePrefix):])
+		if tag == "" || strings.Trim(tag, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			return fmt.Errorf("%s: %q is not a language tag", v.name, key[len(localePrefix):])
+		}
+		if v.translations == nil {
+			v.translations = make(map[string]string)
+		}
+		v.translations[tag] = placeholder.ReplaceAllString(msg, "{$1}")
+	}
+	return nil
+}
+
+// locales returns the sorted tags of the locales any value is translated to.
+func locales(runs [][]Value) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, run := range runs {
+		for _, v := range run {
+			for tag := range v.translations {
+				if !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Arguments:
+//	[1]: type name
+const localizeMethod = `
+func (i %[1]s) Localize(tag string) string {
+	tag = strings.ToLower(strings.Replace(tag, "_", "-", -1))
+	for tag != "" {
+		if msg, ok := _%[1]s_locales[tag]; ok {
+			if s := msg(i); s != "" {
+				return s
+			}
+		}
+		n := strings.LastIndex(tag, "-")
+		if n < 0 {
+			break
+		}
+		tag = tag[:n]
+	}
+	return i.Error()
+}
+`
+
+// buildLocalize generates a message table for each locale, in the same
+// layout as Error's, and the Localize method choosing between them. An
+// untranslated constant has an empty entry, so Localize falls back to the
+// next locale: the tag without its last subtag, then Error.
+func (g *Generator) buildLocalize(runs [][]Value, typeName string, tags []string) {
+	g.Pkg.imports["strings"] = true
+	methods := make([]string, len(tags))
+	for i, tag := range tags {
+		tag := tag
+		prefix := "msg_" + strings.Replace(tag, "-", "_", -1)
+		methods[i] = prefix
+		g.buildLookup(runs, typeName, prefix, prefix, func(v Value) string {
+			return v.translations[tag]
+		})
+	}
+	g.Printf("\nvar _%[1]s_locales = map[string]func(%[1]s) string{\n", typeName)
+	for i, tag := range tags {
+		g.Printf("\t%q: %s.%s,\n", tag, typeName, methods[i])
+	}
+	g.Printf("}\n")
+	g.Printf(localizeMethod, typeName)
+}
+
+// MissingTranslations reports each constant of enums lacking a message in a
+// locale that another constant of its type is translated to. Aliases share
+// the translations of the first constant with their value.
+func MissingTranslations(enums []Enum) []Diagnostic {
+	var diags []Diagnostic
+	for _, e := range enums {
+		all := make(map[string]bool)
+		for _, c := range e.Constants {
+			for tag := range constantLocales(c) {
+				all[tag] = true
+			}
+		}
+		tags := make([]string, 0, len(all))
+		for tag := range all {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+
+		seen := make(map[string]bool)
+		for _, c := range e.Constants {
+			if seen[string(c.Value)] {
+				continue
+			}
+			seen[string(c.Value)] = true
+			have := constantLocales(c)
+			for _, tag := range tags {
+				if !have[tag] {
+					diags = append(diags, Diagnostic{
+						Pos: token.Position{Filename: c.File, Line: c.Line},
+						Msg: fmt.Sprintf("%s.%s has no %s message", e.Type, c.Name, tag),
+					})
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// constantLocales returns the normalized tags c has a message annotation for.
+func constantLocales(c Constant) map[string]bool {
+	tags := make(map[string]bool)
+	for key := range c.Annotations {
+		if strings.HasPrefix(key, localePrefix) {
+			tags[normalizeTag(key[len(localePrefix):])] = true
+		}
+	}
+	return tags
+}
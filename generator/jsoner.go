@@ -0,0 +1,222 @@
+package generator
+
+import "fmt"
+
+// Arguments:
+//	[1]: type name
+//	[2]: further lookups, such as foldedNameLookup
+//	[3]: zero value of the type
+//	[4]: what the lookup takes: "name", or "value" for string codes
+const errStrToValueMap = `func %[1]sString(s string) (%[1]s, error) {
+	if val, ok := _%[1]sNameToValue_map[s]; ok {
+		return val, nil
+	}
+%[2]s
+	return %[3]s, %[1]sNameError{Name: s}
+}
+
+type %[1]sNameError struct {
+	Name string
+}
+
+func (e %[1]sNameError) Error() string {
+	return fmt.Sprintf("%%q is not the %[4]s of type %[1]s", e.Name)
+}
+`
+
+// Arguments:
+//	[1]: type name
+const foldedNameLookup = `
+	if val, ok := _%[1]sFoldedString(s); ok {
+		return val, nil
+	}
+`
+
+// adapted from github.com/alvaroloes/enumer
+// String codes are looked up by value rather than by name.
+func (g *Generator) buildErrStrToValueMap(runs [][]Value, typeName, zero string) {
+	var n int
+	var runID string
+	// called after Stringer and Error are in the buffer
+	g.Printf("\nvar _%sNameToValue_map = map[string]%s{\n", typeName, typeName)
+	hasRuns := len(runs) > 1 && len(runs) <= 10
+
+	for i, values := range runs {
+		if values[0].isString {
+			g.Printf("\t%s: %s,\n", &values[0], values[0].name)
+			continue
+		}
+		if hasRuns {
+			runID = fmt.Sprintf("_%d", i)
+			n = 0
+		} else {
+			runID = ""
+		}
+
+		for _, value := range values {
+			name := g.name(value)
+			g.Printf("\t_%s_name%s[%d:%d]: %s,\n", typeName, runID, n, n+len(name), &value)
+			n += len(name)
+		}
+	}
+
+	g.Printf("}\n\n")
+	// now build our function
+	lookups := ""
+	if g.opts.IgnoreCase {
+		lookups = fmt.Sprintf(foldedNameLookup, typeName)
+	}
+	of := "name"
+	if runs[0][0].isString {
+		of = "value"
+	}
+	g.Printf(errStrToValueMap, typeName, lookups, zero, of)
+}
+
+// Arguments:
+//	[1]: type name
+//	[2]: JSON key of the name
+//	[3]: JSON key of the message
+//	[4]: expression identifying i on the wire
+const jsonMethods = `
+func (i %[1]s) MarshalJSON() ([]byte, error) {
+	b := new(bytes.Buffer)
+	msg, err := json.Marshal(i.Error())
+	if err != nil {
+		return b.Bytes(), err
+	}
+	name, err := json.Marshal(%[4]s)
+	if err != nil {
+		return b.Bytes(), err
+	}
+	json := fmt.Sprintf("{\"%[2]s\":%%s,\"%[3]s\":%%s}", name, msg)
+	b.WriteString(json)
+	return b.Bytes(), nil
+}
+
+type _%[1]s_json struct {
+	Type    string ` + "`" + `json:"%[2]s"` + "`" + `
+	Message string ` + "`" + `json:"%[3]s"` + "`" + `
+}
+
+func (i *%[1]s) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var errData _%[1]s_json
+		if err := json.Unmarshal(data, &errData); err != nil {
+			return fmt.Errorf("Expecting a string or an object, got %%s", data)
+		}
+		name = errData.Type
+	}
+
+	val, err := %[1]sString(name)
+
+	if err != nil {
+		return err
+	}
+
+	*i = val
+
+	return nil
+}
+`
+
+// Arguments:
+//	[1]: type name
+//	[2]: JSON key of the name
+//	[3]: JSON key of the message
+//	[4]: expression identifying i on the wire
+const problemMethods = `
+type _%[1]s_problem struct {
+	Type     string ` + "`" + `json:"%[2]s"` + "`" + `
+	Title    string ` + "`" + `json:"%[3]s"` + "`" + `
+	Status   int    ` + "`" + `json:"status"` + "`" + `
+	Detail   string ` + "`" + `json:"detail,omitempty"` + "`" + `
+	Instance string ` + "`" + `json:"instance,omitempty"` + "`" + `
+}
+
+func (i %[1]s) problem() _%[1]s_problem {
+	return _%[1]s_problem{Type: %[4]s, Title: i.Error(), Status: i.HTTPStatus()}
+}
+
+func (i %[1]s) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.problem())
+}
+
+func (i *%[1]s) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var p _%[1]s_problem
+		if err := json.Unmarshal(data, &p); err != nil {
+			return fmt.Errorf("Expecting a string or an object, got %%s", data)
+		}
+		name = p.Type
+	}
+
+	val, err := %[1]sString(name)
+
+	if err != nil {
+		return err
+	}
+
+	*i = val
+
+	return nil
+}
+
+func (e *%[1]sErr) MarshalJSON() ([]byte, error) {
+	p := e.Code.problem()
+	if e.Cause != nil {
+		p.Detail = e.Cause.Error()
+	}
+	if instance, ok := e.Fields["instance"]; ok {
+		p.Instance = fmt.Sprint(instance)
+	}
+	return json.Marshal(p)
+}
+
+func (e *%[1]sErr) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var p _%[1]s_problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	code, err := %[1]sString(p.Type)
+	if err != nil {
+		return err
+	}
+	*e = %[1]sErr{Code: code}
+	if p.Detail != "" {
+		e.Cause = errors.New(p.Detail)
+	}
+	if p.Instance != "" {
+		e.With("instance", p.Instance)
+	}
+	return nil
+}
+`
+
+// buildJsonMethods generates MarshalJSON and UnmarshalJSON in the shape
+// given by env, which Generate has already checked. key identifies a code
+// in the envelope: its name, or its value for string codes.
+func (g *Generator) buildJsonMethods(typeName string, env Envelope, key string) {
+	code, msg, _ := env.keys()
+	if env.Shape == "rfc7807" {
+		g.Pkg.imports["errors"] = true
+		g.Printf(problemMethods, typeName, code, msg, key)
+		return
+	}
+	g.Pkg.imports["bytes"] = true
+	g.Printf(jsonMethods, typeName, code, msg, key)
+}
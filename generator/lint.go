@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lint loads the package described by cfg and lints each of cfg.Types with
+// msgCase, as Generator.Lint does.
+func Lint(cfg Config, msgCase string) ([]Diagnostic, error) {
+	if len(cfg.Types) == 0 {
+		return nil, errors.New("no type names given")
+	}
+	var g Generator
+	if err := g.ParsePackage(cfg); err != nil {
+		return nil, err
+	}
+	var diags []Diagnostic
+	for _, typeName := range cfg.Types {
+		d, err := g.Lint(typeName, msgCase)
+		if err != nil {
+			return nil, err
+		}
+		diags = append(diags, d...)
+	}
+	return diags, nil
+}
+
+// Lint reports the constants of the named type whose generated methods
+// would be misleading, sorted by position:
+//
+//   - a constant without a message, for which Error returns "";
+//   - a constant with the value of an earlier one, which String and
+//     <Type>String never return;
+//   - a constant with the same message as another value, so the two
+//     cannot be told apart from Error;
+//   - a message ending in punctuation, or whose first letter does not have
+//     the case given by msgCase: "upper", "lower" or "any".
+//
+// Aliases are only reported as such, as their own messages are never used.
+func (g *Generator) Lint(typeName, msgCase string) ([]Diagnostic, error) {
+	if msgCase != "upper" && msgCase != "lower" && msgCase != "any" {
+		return nil, fmt.Errorf("unknown message case %q; want upper, lower or any", msgCase)
+	}
+	values, err := g.collect(typeName)
+	if err != nil {
+		return nil, err
+	}
+	var diags []Diagnostic
+	report := func(v Value, format string, args ...interface{}) {
+		diags = append(diags, Diagnostic{Pos: v.pos, Msg: fmt.Sprintf(format, args...)})
+	}
+	byValue := make(map[string]Value)
+	byMsg := make(map[string]Value)
+	for _, v := range values {
+		if first, ok := byValue[v.str]; ok {
+			report(v, "%s has the same value as %s, so String never returns %s", v.name, first.name, v.name)
+			continue
+		}
+		byValue[v.str] = v
+
+		msg := valueMsg(v)
+		if msg == "" {
+			report(v, "%s has no message, so Error returns \"\"", v.name)
+			continue
+		}
+		if first, ok := byMsg[msg]; ok {
+			report(v, "%s has the same message as %s: %q", v.name, first.name, msg)
+		} else {
+			byMsg[msg] = v
+		}
+		if r, _ := utf8.DecodeLastRuneInString(msg); unicode.IsPunct(r) && r != ')' && r != '"' && r != '}' {
+			report(v, "message of %s ends with punctuation: %q", v.name, msg)
+		}
+		r, _ := utf8.DecodeRuneInString(msg)
+		switch {
+		case msgCase == "upper" && unicode.IsLower(r):
+			report(v, "message of %s should start with an upper-case letter: %q", v.name, msg)
+		case msgCase == "lower" && unicode.IsUpper(r) && !isInitialism(msg):
+			report(v, "message of %s should start with a lower-case letter: %q", v.name, msg)
+		}
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Pos, diags[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return diags, nil
+}
+
+// isInitialism reports whether msg starts with a word of several capital
+// letters, such as "HTTP", which keeps its case in lower-case messages.
+func isInitialism(msg string) bool {
+	word := msg
+	if i := strings.IndexFunc(msg, func(r rune) bool { return !unicode.IsLetter(r) }); i >= 0 {
+		word = msg[:i]
+	}
+	return len(word) > 1 && strings.ToUpper(word) == word
+}
@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+)
+
+// lockHeader starts every lock file written by WriteLock.
+const lockHeader = `# Values of the published error codes, maintained by errorer -lock.
+# Changing or removing one breaks stored codes and clients; errorer then
+# fails unless run with -allow-breaking.
+`
+
+// LockEntry records the value of a constant in a lock file, as a line of
+// the form "Type.Name value".
+type LockEntry struct {
+	Type  string
+	Name  string
+	Value Literal
+}
+
+// ParseLock parses a lock file. Blank lines and lines starting with # are
+// ignored.
+func ParseLock(r io.Reader) ([]LockEntry, error) {
+	var entries []LockEntry
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, value := text, ""
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			name, value = text[:i], strings.TrimSpace(text[i:])
+		}
+		i := strings.Index(name, ".")
+		if i <= 0 || i == len(name)-1 || !isLiteral(value) {
+			return nil, fmt.Errorf("line %d: %q is not of the form Type.Name value", line, text)
+		}
+		entries = append(entries, LockEntry{Type: name[:i], Name: name[i+1:], Value: Literal(value)})
+	}
+	return entries, s.Err()
+}
+
+// isLiteral reports whether s is a JSON number or string, as constant values
+// are written.
+func isLiteral(s string) bool {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case float64, string:
+		return true
+	}
+	return false
+}
+
+// CheckLock reports each locked constant of enums whose value has changed
+// or which no longer exists. Entries of types not in enums are not checked.
+func CheckLock(locked []LockEntry, enums []Enum) []Diagnostic {
+	current := make(map[string]map[string]Constant)
+	for _, e := range enums {
+		byName := make(map[string]Constant)
+		for _, c := range e.Constants {
+			byName[c.Name] = c
+		}
+		current[e.Type] = byName
+	}
+	var diags []Diagnostic
+	for _, l := range locked {
+		byName, ok := current[l.Type]
+		if !ok {
+			continue
+		}
+		c, ok := byName[l.Name]
+		switch {
+		case !ok:
+			diags = append(diags, Diagnostic{Msg: fmt.Sprintf("%s.%s = %s was removed", l.Type, l.Name, l.Value)})
+		case c.Value != l.Value:
+			diags = append(diags, Diagnostic{
+				Pos: token.Position{Filename: c.File, Line: c.Line},
+				Msg: fmt.Sprintf("%s.%s changed from %s to %s", l.Type, l.Name, l.Value, c.Value),
+			})
+		}
+	}
+	return diags
+}
+
+// WriteLock writes a lock file recording the constants of enums, in
+// declaration order, aliases included. The entries of other types in locked
+// are kept, so that several runs of errorer can share a lock file.
+func WriteLock(w io.Writer, enums []Enum, locked []LockEntry) error {
+	b := new(bytes.Buffer)
+	b.WriteString(lockHeader)
+	byType := make(map[string]Enum)
+	for _, e := range enums {
+		byType[e.Type] = e
+	}
+	written := make(map[string]bool)
+	last := ""
+	writeEnum := func(e Enum) {
+		written[e.Type] = true
+		last = e.Type
+		fmt.Fprintf(b, "\n")
+		for _, c := range e.Constants {
+			fmt.Fprintf(b, "%s.%s %s\n", e.Type, c.Name, c.Value)
+		}
+	}
+	// Types keep their place in the file.
+	for _, l := range locked {
+		if e, ok := byType[l.Type]; ok {
+			if !written[l.Type] {
+				writeEnum(e)
+			}
+			continue
+		}
+		if l.Type != last {
+			fmt.Fprintf(b, "\n")
+			last = l.Type
+		}
+		fmt.Fprintf(b, "%s.%s %s\n", l.Type, l.Name, l.Value)
+	}
+	for _, e := range enums {
+		if !written[e.Type] {
+			writeEnum(e)
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteProto writes a proto3 file declaring an enum for each of enums, with
+// the same numeric values as the Go constants. pkg is the proto package, and
+// is left out if empty. args is the command line recorded in the header.
+func WriteProto(w io.Writer, enums []Enum, pkg string, args []string) error {
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, "// Code generated by \"errorer %s\"; DO NOT EDIT.\n\n", strings.Join(args, " "))
+	fmt.Fprintf(b, "syntax = \"proto3\";\n")
+	if pkg != "" {
+		fmt.Fprintf(b, "\npackage %s;\n", pkg)
+	}
+	for _, e := range enums {
+		if err := writeProtoEnum(b, e); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeProtoEnum declares e as a proto enum. Value names are prefixed with
+// the type name, as enum values share the scope of their package. proto3
+// requires the first value to be zero, so the first zero constant is moved
+// to the front, or <TYPE>_UNSPECIFIED is added if there is none. Aliases
+// need the allow_alias option.
+func writeProtoEnum(b *bytes.Buffer, e Enum) error {
+	prefix := upperSnake(e.Type) + "_"
+	zero, hasAlias := -1, false
+	seen := make(map[Literal]bool)
+	for i, c := range e.Constants {
+		if _, ok := c.Value.stringValue(); ok {
+			return fmt.Errorf("%s is a string type, which has no proto enum", e.Type)
+		}
+		if _, err := strconv.ParseInt(string(c.Value), 10, 32); err != nil {
+			return fmt.Errorf("%s: value %s of %s does not fit in a proto enum", c.Name, c.Value, e.Type)
+		}
+		if zero < 0 && c.Value == "0" {
+			zero = i
+		}
+		hasAlias = hasAlias || seen[c.Value]
+		seen[c.Value] = true
+	}
+
+	fmt.Fprintf(b, "\nenum %s {\n", e.Type)
+	if hasAlias {
+		fmt.Fprintf(b, "  option allow_alias = true;\n")
+	}
+	constants := e.Constants
+	if zero < 0 {
+		fmt.Fprintf(b, "  %sUNSPECIFIED = 0;\n", prefix)
+	} else {
+		constants = append([]Constant{e.Constants[zero]}, e.Constants[:zero]...)
+		constants = append(constants, e.Constants[zero+1:]...)
+	}
+	for _, c := range constants {
+		name := upperSnake(c.Name)
+		if !strings.HasPrefix(name, prefix) {
+			name = prefix + name
+		}
+		fmt.Fprintf(b, "  %s = %s;", name, c.Value)
+		if c.Message != "" {
+			fmt.Fprintf(b, " // %s", strings.Join(strings.Fields(c.Message), " "))
+		}
+		fmt.Fprintf(b, "\n")
+	}
+	fmt.Fprintf(b, "}\n")
+	return nil
+}
+
+// Arguments:
+//	[1]: type name
+//	[2]: quoted error domain
+//	[3]: expression identifying i as the reason
+//	[4]: zero value of the type
+//	[5]: function returning the code for a reason
+const errorInfoMethods = `
+const _%[1]s_domain = %[2]s
+
+type %[1]sErrorInfo struct {
+	Reason   string
+	Domain   string
+	Metadata map[string]string
+}
+
+func (i %[1]s) ErrorInfo() *%[1]sErrorInfo {
+	return &%[1]sErrorInfo{Reason: %[3]s, Domain: _%[1]s_domain}
+}
+
+func (e *%[1]sErr) ErrorInfo() *%[1]sErrorInfo {
+	info := e.Code.ErrorInfo()
+	if len(e.Fields) > 0 {
+		info.Metadata = make(map[string]string, len(e.Fields))
+		for k, v := range e.Fields {
+			info.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+	return info
+}
+
+func %[1]sFromErrorInfo(info *%[1]sErrorInfo) (%[1]s, error) {
+	if info == nil {
+		return %[4]s, fmt.Errorf("no error info for %[1]s")
+	}
+	if info.Domain != _%[1]s_domain {
+		return %[4]s, fmt.Errorf("error info domain %%q is not %%q", info.Domain, _%[1]s_domain)
+	}
+	return %[5]s(info.Reason)
+}
+`
+
+// Arguments:
+//	[1]: type name
+const reasonLookup = `
+func _%[1]sReason(i %[1]s) string {
+	if reason, ok := _%[1]s_reason_map[i]; ok {
+		return reason
+	}
+	return i.String()
+}
+
+func _%[1]sFromReason(s string) (%[1]s, error) {
+	if val, ok := _%[1]sReasonToValue_map[s]; ok {
+		return val, nil
+	}
+	return 0, %[1]sNameError{Name: s}
+}
+`
+
+// buildErrorInfo generates conversions between the type and <Type>ErrorInfo,
+// a struct mirroring the google.rpc.ErrorInfo error detail, whose reason is
+// the constant name, or the value of a string code. It is called after
+// buildWrapper, as the wrapper's fields become the detail's metadata.
+//
+// String returns the transformed names, so under -transform the reasons of
+// integer codes are looked up in maps of the constant names.
+func (g *Generator) buildErrorInfo(runs [][]Value, typeName, domain, key, zero string) {
+	lookup := typeName + "String"
+	if g.opts.Transform != "" && !runs[0][0].isString {
+		g.Printf("\nvar _%[1]s_reason_map = map[%[1]s]string{\n", typeName)
+		for _, run := range runs {
+			for _, v := range run {
+				g.Printf("\t%s: %q,\n", &v, v.name)
+			}
+		}
+		g.Printf("}\n")
+		g.Printf("\nvar _%sReasonToValue_map = map[string]%s{\n", typeName, typeName)
+		for _, run := range runs {
+			for _, v := range run {
+				g.Printf("\t%q: %s,\n", v.name, &v)
+			}
+		}
+		g.Printf("}\n")
+		g.Printf(reasonLookup, typeName)
+		key, lookup = "_"+typeName+"Reason(i)", "_"+typeName+"FromReason"
+	}
+	g.Printf(errorInfoMethods, typeName, strconv.Quote(domain), key, zero, lookup)
+}
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonSchemaDialect is the JSON Schema version of the documents WriteSchema writes.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is the subset of JSON Schema needed to describe the JSON envelope
+// produced by the generated MarshalJSON. Fields are in output order.
+type schema struct {
+	Schema           string             `json:"$schema,omitempty"`
+	Title            string             `json:"title,omitempty"`
+	Const            string             `json:"const,omitempty"`
+	Description      string             `json:"description,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	Enum             []string           `json:"enum,omitempty"`
+	EnumDescriptions []string           `json:"x-enum-descriptions,omitempty"`
+	OneOf            []*schema          `json:"oneOf,omitempty"`
+	Properties       schemaProperties   `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+	Defs             map[string]*schema `json:"$defs,omitempty"`
+}
+
+// schemaProperties are the properties of an object schema, in output order.
+type schemaProperties []schemaProperty
+
+type schemaProperty struct {
+	name   string
+	schema *schema
+}
+
+func (p schemaProperties) MarshalJSON() ([]byte, error) {
+	b := new(bytes.Buffer)
+	b.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(prop.name)
+		value, err := json.Marshal(prop.schema)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// WriteSchema writes a schema for the JSON envelope of each of enums, in the
+// shape given by env. The format is either "jsonschema", a JSON Schema
+// document with one definition per type, or "openapi", an OpenAPI 3
+// components object.
+func WriteSchema(w io.Writer, enums []Enum, format string, env Envelope) error {
+	members, err := env.members()
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	switch format {
+	case "jsonschema":
+		root := &schema{Schema: jsonSchemaDialect, Defs: make(map[string]*schema)}
+		for _, e := range enums {
+			root.Defs[e.Type] = envelopeSchema(e, members, false)
+		}
+		doc = root
+	case "openapi":
+		var root openAPIDocument
+		root.Components.Schemas = make(map[string]*schema)
+		for _, e := range enums {
+			root.Components.Schemas[e.Type] = envelopeSchema(e, members, true)
+		}
+		doc = root
+	default:
+		return fmt.Errorf("unknown schema format %q", format)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// envelopeSchema describes the envelope object for e. The property holding
+// the name lists the names MarshalJSON can produce: one per distinct value.
+// OpenAPI 3.0 has no const keyword, so for it the per-value descriptions
+// use the x-enum-descriptions extension instead of oneOf.
+func envelopeSchema(e Enum, members []envelopeMember, openAPI bool) *schema {
+	typ := &schema{Type: "string"}
+	seen := make(map[Literal]bool)
+	for _, c := range e.Constants {
+		if seen[c.Value] {
+			continue
+		}
+		seen[c.Value] = true
+		if openAPI {
+			typ.Enum = append(typ.Enum, c.wireName())
+			typ.EnumDescriptions = append(typ.EnumDescriptions, c.Message)
+		} else {
+			typ.OneOf = append(typ.OneOf, &schema{Const: c.wireName(), Description: c.Message})
+		}
+	}
+	s := &schema{Title: e.Type, Type: "object"}
+	for _, m := range members {
+		prop := &schema{Type: m.typ}
+		if m.field == "code" {
+			prop = typ
+		}
+		s.Properties = append(s.Properties, schemaProperty{m.key, prop})
+		if m.required {
+			s.Required = append(s.Required, m.key)
+		}
+	}
+	return s
+}
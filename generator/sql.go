@@ -0,0 +1,128 @@
+package generator
+
+import "fmt"
+
+// Arguments:
+//	[1]: type name
+const sqlNameMethods = `
+func (i %[1]s) Value() (driver.Value, error) {
+	name := i.String()
+	if _, ok := _%[1]sNameToValue_map[name]; !ok {
+		return nil, fmt.Errorf("storing %[1]s: %%d has no name", i)
+	}
+	return name, nil
+}
+
+func (i *%[1]s) Scan(src interface{}) error {
+	var name string
+	switch v := src.(type) {
+	case string:
+		name = v
+	case []byte:
+		name = string(v)
+	case nil:
+		return fmt.Errorf("scanning %[1]s: NULL is not a %[1]s")
+	default:
+		return fmt.Errorf("scanning %[1]s: cannot convert %%T to a name", src)
+	}
+	val, ok := _%[1]sNameToValue_map[name]
+	if !ok {
+		return fmt.Errorf("scanning %[1]s: %%q is not the name of a %[1]s", name)
+	}
+	*i = val
+	return nil
+}
+`
+
+// Arguments:
+//	[1]: type name
+const sqlIntMethods = `
+func (i %[1]s) Value() (driver.Value, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("storing %[1]s: %%d is not a %[1]s", i)
+	}
+	return int64(i), nil
+}
+
+func (i *%[1]s) Scan(src interface{}) error {
+	var n int64
+	switch v := src.(type) {
+	case int64:
+		n = v
+	case string, []byte:
+		parsed, err := strconv.ParseInt(fmt.Sprintf("%%s", v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("scanning %[1]s: %%q is not an integer", v)
+		}
+		n = parsed
+	case nil:
+		return fmt.Errorf("scanning %[1]s: NULL is not a %[1]s")
+	default:
+		return fmt.Errorf("scanning %[1]s: cannot convert %%T to an integer", src)
+	}
+	val := %[1]s(n)
+	if !val.IsValid() || int64(val) != n {
+		return fmt.Errorf("scanning %[1]s: %%d is not a %[1]s", n)
+	}
+	*i = val
+	return nil
+}
+`
+
+// Arguments:
+//	[1]: type name
+const sqlStringMethods = `
+func (i %[1]s) Value() (driver.Value, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("storing %[1]s: %%q is not a %[1]s", string(i))
+	}
+	return string(i), nil
+}
+
+func (i *%[1]s) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+		return fmt.Errorf("scanning %[1]s: NULL is not a %[1]s")
+	default:
+		return fmt.Errorf("scanning %[1]s: cannot convert %%T to a string", src)
+	}
+	val := %[1]s(s)
+	if !val.IsValid() {
+		return fmt.Errorf("scanning %[1]s: %%q is not a %[1]s", s)
+	}
+	*i = val
+	return nil
+}
+`
+
+// checkSQLMode reports whether mode is a valid -sql mode.
+func checkSQLMode(mode string) error {
+	switch mode {
+	case "", "name", "int":
+		return nil
+	}
+	return fmt.Errorf("unknown SQL mode %q; want name or int", mode)
+}
+
+// buildSQLMethods generates Value and Scan, storing the codes by name or by
+// number according to mode. String codes are stored by value, which
+// Generate has checked mode allows. Both reject values that are not
+// constants of the type.
+func (g *Generator) buildSQLMethods(runs [][]Value, typeName, mode string) {
+	g.Pkg.imports["database/sql/driver"] = true
+	if runs[0][0].isString {
+		g.Printf(sqlStringMethods, typeName)
+		return
+	}
+	if mode == "int" {
+		g.Pkg.imports["strconv"] = true
+		g.Printf(sqlIntMethods, typeName)
+		return
+	}
+	g.Printf(sqlNameMethods, typeName)
+}
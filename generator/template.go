@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// placeholder matches a typed parameter in a message template, e.g.
+// "User {id:string} could not be found".
+var placeholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*):([^{}]+)\}`)
+
+// Param is a typed parameter of a message template.
+type Param struct {
+	name string // Name of the constructor argument.
+	typ  string // Type of the argument as it is written in the generated file.
+}
+
+// parseTemplate extracts the typed parameters from msg. It returns the message
+// with the types removed, used by the plain Error method, and the equivalent
+// fmt format used to render the parameters. Each parameter type is checked
+// against the package scope at pos.
+func (f *File) parseTemplate(msg string, pos token.Pos) (string, string, []Param, error) {
+	matches := placeholder.FindAllStringSubmatchIndex(msg, -1)
+	if matches == nil {
+		return msg, "", nil, nil
+	}
+	msg = strings.TrimSuffix(msg, "\n")
+	var plain, format strings.Builder
+	var params []Param
+	last := 0
+	for _, m := range matches {
+		name, expr := msg[m[2]:m[3]], strings.TrimSpace(msg[m[4]:m[5]])
+		// The name becomes an argument of the constructor.
+		if name == "_" || token.IsKeyword(name) {
+			return "", "", nil, fmt.Errorf("parameter name %s is not a usable Go identifier", name)
+		}
+		for _, p := range params {
+			if p.name == name {
+				return "", "", nil, fmt.Errorf("duplicate parameter %s", name)
+			}
+		}
+		tv, err := types.Eval(f.pkg.fset, f.pkg.typesPkg, pos, expr)
+		if terr, ok := err.(types.Error); ok {
+			// Drop the position within the evaluated expression.
+			return "", "", nil, fmt.Errorf("parameter %s: %s", name, terr.Msg)
+		}
+		if err != nil {
+			return "", "", nil, fmt.Errorf("parameter %s: %s", name, err)
+		}
+		if !tv.IsType() {
+			return "", "", nil, fmt.Errorf("parameter %s: %s is not a type", name, expr)
+		}
+		typ := types.TypeString(tv.Type, f.pkg.qualifier)
+		params = append(params, Param{name: name, typ: typ})
+
+		plain.WriteString(msg[last:m[0]])
+		plain.WriteString("{" + name + "}")
+		format.WriteString(strings.Replace(msg[last:m[0]], "%", "%%", -1))
+		format.WriteString("%v")
+		last = m[1]
+	}
+	plain.WriteString(msg[last:])
+	format.WriteString(strings.Replace(msg[last:], "%", "%%", -1))
+	return plain.String(), format.String(), params, nil
+}
+
+// qualifier names packages other than the one being generated, recording
+// them as imports of the generated file.
+func (pkg *Package) qualifier(other *types.Package) string {
+	if other == pkg.typesPkg {
+		return ""
+	}
+	pkg.imports[other.Path()] = true
+	return other.Name()
+}
+
+// hasTemplates reports whether any value has a message template.
+func hasTemplates(runs [][]Value) bool {
+	for _, run := range runs {
+		for _, v := range run {
+			if v.format != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Arguments:
+//	[1]: type name
+const argsType = `
+type %[1]sArgs struct {
+	Code %[1]s
+	Args []interface{}
+}
+
+func (e *%[1]sArgs) Error() string {
+	return fmt.Sprintf(_%[1]s_format[e.Code], e.Args...)
+}
+
+func (e *%[1]sArgs) Unwrap() error {
+	return e.Code
+}
+`
+
+// buildTemplates generates the <Type>Args wrapper and a <Name>f constructor
+// for each constant with a message template.
+func (g *Generator) buildTemplates(runs [][]Value, typeName string) {
+	g.Printf(argsType, typeName)
+	g.Printf("\nvar _%s_format = map[%s]string{\n", typeName, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			if value.format != "" {
+				g.Printf("\t%s: %q,\n", value.name, value.format)
+			}
+		}
+	}
+	g.Printf("}\n")
+	for _, values := range runs {
+		for _, value := range values {
+			if value.format == "" {
+				continue
+			}
+			args := make([]string, len(value.params))
+			names := make([]string, len(value.params))
+			for i, p := range value.params {
+				args[i] = p.name + " " + p.typ
+				names[i] = p.name
+			}
+			g.Printf("\nfunc %sf(%s) *%sArgs {\n", value.name, strings.Join(args, ", "), typeName)
+			g.Printf("\treturn &%sArgs{Code: %s, Args: []interface{}{%s}}\n", typeName, value.name, strings.Join(names, ", "))
+			g.Printf("}\n")
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package generator
+
+// Arguments:
+//	[1]: type name
+//	[2]: expression identifying i as text
+const textMethods = `
+func (i %[1]s) MarshalText() ([]byte, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("no name for %%s", i)
+	}
+	return []byte(%[2]s), nil
+}
+
+func (i *%[1]s) UnmarshalText(text []byte) error {
+	val, err := %[1]sString(string(text))
+	if err != nil {
+		return err
+	}
+	*i = val
+	return nil
+}
+`
+
+// buildTextMethods generates MarshalText and UnmarshalText from the names,
+// or the values of string codes, so the codes can be JSON map keys, flags
+// and YAML or TOML values. Values without a name fail to marshal rather
+// than produce a name that does not unmarshal.
+func (g *Generator) buildTextMethods(typeName, key string) {
+	g.Printf(textMethods, typeName, key)
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// transforms maps the -transform names to the functions rewriting constant
+// names for String and the JSON envelope.
+var transforms = map[string]func(string) string{
+	"snake":       func(s string) string { return strings.ToLower(strings.Join(words(s), "_")) },
+	"kebab":       func(s string) string { return strings.ToLower(strings.Join(words(s), "-")) },
+	"upper-snake": upperSnake,
+	"camel":       camel,
+}
+
+// transformName returns the function for the named transform, which is the
+// identity for "".
+func transformName(name string) (func(string) string, error) {
+	if name == "" {
+		return func(s string) string { return s }, nil
+	}
+	if t, ok := transforms[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown transform %q; want snake, kebab, upper-snake or camel", name)
+}
+
+// words splits a Go identifier into words at case changes and underscores,
+// keeping initialisms together: "HTTPNotFound" is "HTTP", "Not", "Found".
+func words(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	for i, r := range runes {
+		if r == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i > start && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// upperSnake converts a Go identifier to UPPER_SNAKE_CASE: "HTTPNotFound"
+// becomes "HTTP_NOT_FOUND".
+func upperSnake(s string) string {
+	return strings.ToUpper(strings.Join(words(s), "_"))
+}
+
+// camel converts a Go identifier to lower camel case: "HTTPNotFound" becomes
+// "httpNotFound".
+func camel(s string) string {
+	w := words(s)
+	if len(w) == 0 {
+		return s
+	}
+	w[0] = strings.ToLower(w[0])
+	return strings.Join(w, "")
+}
+
+// foldName is the key of the case-insensitive lookup: the name in lower
+// case without separators, so "not_found", "not-found" and "NotFound" match.
+func foldName(s string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+}
+
+// Arguments:
+//	[1]: type name
+const foldedLookup = `
+var _%[1]s_folder = strings.NewReplacer("_", "", "-", "")
+
+func _%[1]sFoldedString(s string) (%[1]s, bool) {
+	val, ok := _%[1]sFoldedNameToValue_map[strings.ToLower(_%[1]s_folder.Replace(s))]
+	return val, ok
+}
+`
+
+// buildFoldedLookup generates the map used by <Type>String to look up
+// names, or the values of string codes, ignoring case and separators. Names that fold to the same key are
+// reported, as the lookup could not tell them apart.
+func (g *Generator) buildFoldedLookup(runs [][]Value, typeName string) error {
+	g.Pkg.imports["strings"] = true
+	seen := make(map[string]string)
+	g.Printf("\nvar _%sFoldedNameToValue_map = map[string]%s{\n", typeName, typeName)
+	for _, run := range runs {
+		for _, v := range run {
+			key := foldName(v.name)
+			if v.isString {
+				key = foldName(v.stringValue())
+			}
+			if other, ok := seen[key]; ok {
+				return &Diagnostic{Pos: v.pos, Msg: fmt.Sprintf("%s and %s are the same name ignoring case", other, v.name)}
+			}
+			seen[key] = v.name
+			g.Printf("\t%q: %s,\n", key, &v)
+		}
+	}
+	g.Printf("}\n")
+	g.Printf(foldedLookup, typeName)
+	return nil
+}
@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WriteTypeScript writes a TypeScript module mirroring enums: for each type a
+// union of its names, a map of messages and numeric values, the JSON
+// envelope produced by MarshalJSON in the shape given by env and a type
+// guard for it. args is the command line recorded in the header.
+func WriteTypeScript(w io.Writer, enums []Enum, env Envelope, args []string) error {
+	members, err := env.members()
+	if err != nil {
+		return err
+	}
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, "// Code generated by \"errorer %s\"; DO NOT EDIT.\n", strings.Join(args, " "))
+	for _, e := range enums {
+		writeTypeScriptEnum(b, e, members)
+	}
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// typeScriptIdent matches the JSON keys that need no quotes in TypeScript;
+// -json-fields and -transform=kebab also allow keys such as "error-code".
+var typeScriptIdent = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptKey returns key as an object literal or interface key.
+func typeScriptKey(key string) string {
+	if typeScriptIdent.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// typeScriptTypes maps the JSON types of envelope members to TypeScript.
+var typeScriptTypes = map[string]string{
+	"string":  "string",
+	"integer": "number",
+}
+
+// writeTypeScriptEnvelope writes the envelope interface for e and its type
+// guard, which checks the required members.
+func writeTypeScriptEnvelope(b *bytes.Buffer, e Enum, members []envelopeMember) {
+	fmt.Fprintf(b, "\nexport interface %sEnvelope {\n", e.Type)
+	for _, m := range members {
+		typ := typeScriptTypes[m.typ]
+		if m.field == "code" {
+			typ = e.Type
+		}
+		optional := "?"
+		if m.required {
+			optional = ""
+		}
+		fmt.Fprintf(b, "  %s%s: %s;\n", typeScriptKey(m.key), optional, typ)
+	}
+	fmt.Fprintf(b, "}\n")
+
+	fmt.Fprintf(b, "\nexport function is%[1]sEnvelope(value: unknown): value is %[1]sEnvelope {\n", e.Type)
+	fmt.Fprintf(b, "  if (typeof value !== \"object\" || value === null) {\n")
+	fmt.Fprintf(b, "    return false;\n")
+	fmt.Fprintf(b, "  }\n")
+	var fields, checks []string
+	for _, m := range members {
+		if !m.required {
+			continue
+		}
+		key := "v." + m.key
+		if !typeScriptIdent.MatchString(m.key) {
+			key = fmt.Sprintf("v[%q]", m.key)
+		}
+		fields = append(fields, typeScriptKey(m.key)+"?: unknown")
+		checks = append(checks, fmt.Sprintf("typeof %s === %q", key, typeScriptTypes[m.typ]))
+		if m.field == "code" {
+			checks = append(checks, fmt.Sprintf("Object.prototype.hasOwnProperty.call(%sMessages, %s)", e.Type, key))
+		}
+	}
+	fmt.Fprintf(b, "  const v = value as { %s };\n", strings.Join(fields, "; "))
+	fmt.Fprintf(b, "  return (\n")
+	fmt.Fprintf(b, "    %s\n", strings.Join(checks, " &&\n    "))
+	fmt.Fprintf(b, "  );\n")
+	fmt.Fprintf(b, "}\n")
+}
+
+func writeTypeScriptEnum(b *bytes.Buffer, e Enum, members []envelopeMember) {
+	// Only one name per value ever appears in the envelope.
+	var constants []Constant
+	seen := make(map[Literal]bool)
+	for _, c := range e.Constants {
+		if !seen[c.Value] {
+			seen[c.Value] = true
+			constants = append(constants, c)
+		}
+	}
+	quote := func(s string) string {
+		q, _ := json.Marshal(s)
+		return string(q)
+	}
+
+	fmt.Fprintf(b, "\nexport const %s = {\n", e.Type)
+	for _, c := range constants {
+		fmt.Fprintf(b, "  %s: %s,\n", c.Name, quote(c.wireName()))
+	}
+	fmt.Fprintf(b, "} as const;\n\n")
+	fmt.Fprintf(b, "export type %[1]s = (typeof %[1]s)[keyof typeof %[1]s];\n", e.Type)
+
+	fmt.Fprintf(b, "\nexport const %[1]sMessages: Record<%[1]s, string> = {\n", e.Type)
+	for _, c := range constants {
+		fmt.Fprintf(b, "  %s: %s,\n", typeScriptKey(c.wireName()), quote(c.Message))
+	}
+	fmt.Fprintf(b, "};\n")
+
+	// The values of string codes are already their names.
+	if _, ok := constants[0].Value.stringValue(); !ok {
+		fmt.Fprintf(b, "\nexport const %[1]sValues: Record<%[1]s, number> = {\n", e.Type)
+		for _, c := range constants {
+			fmt.Fprintf(b, "  %s: %s,\n", typeScriptKey(c.wireName()), c.Value)
+		}
+		fmt.Fprintf(b, "};\n")
+	}
+
+	writeTypeScriptEnvelope(b, e, members)
+}
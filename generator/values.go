@@ -0,0 +1,67 @@
+package generator
+
+import "strings"
+
+// Arguments:
+//	[1]: type name
+const valuesFuncs = `
+func %[1]sValues() []%[1]s {
+	return append([]%[1]s(nil), _%[1]s_values...)
+}
+
+func %[1]sNames() []string {
+	names := make([]string, len(_%[1]s_values))
+	for i, v := range _%[1]s_values {
+		names[i] = v.String()
+	}
+	return names
+}
+`
+
+// buildValues generates <Type>Values and <Type>Names, listing each distinct
+// value once in increasing order, and IsValid, which reports whether a value
+// is one of them.
+func (g *Generator) buildValues(runs [][]Value, typeName string) {
+	g.Printf("\nvar _%s_values = []%s{\n", typeName, typeName)
+	for _, values := range runs {
+		for _, value := range values {
+			g.Printf("\t%s,\n", value.name)
+		}
+	}
+	g.Printf("}\n")
+	g.Printf(valuesFuncs, typeName)
+
+	g.Printf("\nfunc (i %s) IsValid() bool {\n", typeName)
+	if runs[0][0].isString {
+		names := make([]string, len(runs))
+		for i, values := range runs {
+			names[i] = values[0].name
+		}
+		g.Printf("\tswitch i {\n")
+		g.Printf("\tcase %s:\n", strings.Join(names, ", "))
+		g.Printf("\t\treturn true\n")
+		g.Printf("\t}\n")
+		g.Printf("\treturn false\n")
+		g.Printf("}\n")
+		return
+	}
+	if len(runs) > 10 {
+		// The name table is a map, as in buildMap.
+		g.Printf("\t_, ok := _%s_name_map[i]\n", typeName)
+		g.Printf("\treturn ok\n")
+		g.Printf("}\n")
+		return
+	}
+	g.Printf("\tswitch {\n")
+	for _, values := range runs {
+		if len(values) == 1 {
+			g.Printf("\tcase i == %s:\n", values[0].name)
+		} else {
+			g.Printf("\tcase %s <= i && i <= %s:\n", values[0].name, values[len(values)-1].name)
+		}
+		g.Printf("\t\treturn true\n")
+	}
+	g.Printf("\t}\n")
+	g.Printf("\treturn false\n")
+	g.Printf("}\n")
+}
@@ -0,0 +1,50 @@
+package generator
+
+// Arguments:
+//	[1]: type name
+const wrapMethods = `
+type %[1]sErr struct {
+	Code   %[1]s
+	Cause  error
+	Fields map[string]interface{}
+}
+
+func (i %[1]s) Wrap(cause error) *%[1]sErr {
+	return &%[1]sErr{Code: i, Cause: cause}
+}
+
+func (e *%[1]sErr) With(key string, value interface{}) *%[1]sErr {
+	if e.Fields == nil {
+		e.Fields = make(map[string]interface{})
+	}
+	e.Fields[key] = value
+	return e
+}
+
+func (e *%[1]sErr) Error() string {
+	if e.Cause == nil {
+		return e.Code.Error()
+	}
+	return e.Code.Error() + ": " + e.Cause.Error()
+}
+
+func (e *%[1]sErr) Unwrap() error {
+	return e.Cause
+}
+
+func (e *%[1]sErr) Is(target error) bool {
+	switch t := target.(type) {
+	case %[1]s:
+		return e.Code == t
+	case *%[1]sErr:
+		return e.Code == t.Code
+	}
+	return false
+}
+`
+
+// buildWrapper generates the <Type>Err wrapper, which attaches a cause and
+// fields to a code while still matching the code with errors.Is.
+func (g *Generator) buildWrapper(typeName string) {
+	g.Printf(wrapMethods, typeName)
+}
@@ -0,0 +1,12 @@
+// Errorerlint runs the errorer lint checks as a standalone command, or as
+// go vet -vettool=$(which errorerlint).
+package main
+
+import (
+	"github.com/iantanwx/errorer/lint"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(lint.Analyzer, lint.Exhaustive)
+}
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/iantanwx/errorer/generator"
+	"golang.org/x/tools/go/analysis"
+)
+
+// ignoreDirective on a switch statement, or on the line above it, exempts
+// the switch from Exhaustive.
+const ignoreDirective = "//errorer:exhaustive-ignore"
+
+// Exhaustive reports switch statements over types generated by errorer that
+// miss some of their values, outside generated files. The values of types
+// from other packages are passed along as facts.
+var Exhaustive = &analysis.Analyzer{
+	Name:      "errorerexhaustive",
+	Doc:       "check that switches over errorer types handle every value\n\nSwitches in generated files are skipped. A switch is also exempt if it or the line above it has an " + ignoreDirective + " comment.",
+	Run:       runExhaustive,
+	FactTypes: []analysis.Fact{new(enumFact)},
+}
+
+var defaultExhaustive bool
+
+func init() {
+	Exhaustive.Flags.BoolVar(&defaultExhaustive, "default", false, "treat a switch with a default case as exhaustive")
+}
+
+// enumFact records the constants of a type errorer generates code for.
+type enumFact struct {
+	Constants []enumConstant // In declaration order, aliases included.
+}
+
+type enumConstant struct {
+	Name  string
+	Value string // As printed by constant.Value.ExactString.
+}
+
+func (*enumFact) AFact() {}
+
+func (f *enumFact) String() string {
+	var names []string
+	for _, c := range f.Constants {
+		names = append(names, c.Name)
+	}
+	return "errorer(" + strings.Join(names, ", ") + ")"
+}
+
+func runExhaustive(pass *analysis.Pass) (interface{}, error) {
+	if directives := generator.Directives(pass.Files); len(directives) > 0 {
+		g := generator.Generator{Pkg: generator.NewPackage(pass.Fset, pass.Files, pass.Pkg, pass.TypesInfo)}
+		for _, d := range directives {
+			for _, typeName := range d.Types {
+				obj, ok := pass.Pkg.Scope().Lookup(typeName).(*types.TypeName)
+				if !ok {
+					continue // Generate reports it.
+				}
+				e, err := g.Enum(typeName)
+				if err != nil {
+					continue // Lint reports it.
+				}
+				fact := new(enumFact)
+				for _, c := range e.Constants {
+					fact.Constants = append(fact.Constants, enumConstant{Name: c.Name, Value: exactString(c.Value)})
+				}
+				pass.ExportObjectFact(obj, fact)
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		// errorer's own switches, such as HTTPStatus, list only the
+		// annotated constants.
+		if ast.IsGenerated(file) {
+			continue
+		}
+		ignored := make(map[int]bool)
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if strings.HasPrefix(c.Text, ignoreDirective) {
+					ignored[pass.Fset.Position(c.Pos()).Line] = true
+				}
+			}
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			stmt, ok := node.(*ast.SwitchStmt)
+			if !ok || stmt.Tag == nil {
+				return true
+			}
+			line := pass.Fset.Position(stmt.Pos()).Line
+			if !ignored[line] && !ignored[line-1] {
+				checkSwitch(pass, stmt)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// checkSwitch reports the values of the switch tag's type that no case of
+// stmt handles, naming each by its first constant.
+func checkSwitch(pass *analysis.Pass, stmt *ast.SwitchStmt) {
+	named, ok := pass.TypesInfo.TypeOf(stmt.Tag).(*types.Named)
+	if !ok {
+		return
+	}
+	fact := new(enumFact)
+	if !pass.ImportObjectFact(named.Obj(), fact) {
+		return
+	}
+	handled := make(map[string]bool)
+	for _, clause := range stmt.Body.List {
+		cc := clause.(*ast.CaseClause)
+		if cc.List == nil && defaultExhaustive {
+			return
+		}
+		for _, expr := range cc.List {
+			if tv, ok := pass.TypesInfo.Types[expr]; ok && tv.Value != nil {
+				handled[tv.Value.ExactString()] = true
+			}
+		}
+	}
+	var missing []string
+	for _, c := range fact.Constants {
+		if !handled[c.Value] {
+			missing = append(missing, c.Name)
+			handled[c.Value] = true // Name each value once.
+		}
+	}
+	if len(missing) > 0 {
+		pass.Report(analysis.Diagnostic{
+			Pos:     stmt.Pos(),
+			End:     stmt.Body.Lbrace + token.Pos(1),
+			Message: fmt.Sprintf("missing cases in switch of type %s: %s", typeString(pass, named), strings.Join(missing, ", ")),
+		})
+	}
+}
+
+// exactString returns the value of a constant as go/constant prints it.
+func exactString(l generator.Literal) string {
+	var s string
+	if json.Unmarshal([]byte(l), &s) == nil {
+		return constant.MakeString(s).ExactString()
+	}
+	return string(l)
+}
+
+// typeString names t as code in the package being checked would, qualifying
+// it by package name if it is imported.
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == pass.Pkg {
+			return ""
+		}
+		return p.Name()
+	})
+}
@@ -0,0 +1,59 @@
+// Package lint provides a go/analysis Analyzer running errorer's lint
+// checks on the types named by //go:generate errorer directives, so they can
+// run in go vet or gopls alongside other analyzers.
+package lint
+
+import (
+	"go/token"
+
+	"github.com/iantanwx/errorer/generator"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer reports constants without messages, aliased values, duplicate
+// messages and messages breaking the style rules. See generator.Generator.Lint.
+var Analyzer = &analysis.Analyzer{
+	Name: "errorerlint",
+	Doc:  "check the error codes of types generated by errorer\n\nReports constants without messages, aliased values, duplicate messages and badly styled messages.",
+	Run:  run,
+}
+
+var msgCase string
+
+func init() {
+	Analyzer.Flags.StringVar(&msgCase, "case", "upper", "case messages must start with: upper, lower or any")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	directives := generator.Directives(pass.Files)
+	if len(directives) == 0 {
+		return nil, nil
+	}
+	g := generator.Generator{Pkg: generator.NewPackage(pass.Fset, pass.Files, pass.Pkg, pass.TypesInfo)}
+	for _, d := range directives {
+		for _, typeName := range d.Types {
+			diags, err := g.Lint(typeName, msgCase)
+			if diag, ok := err.(*generator.Diagnostic); ok {
+				diags = []generator.Diagnostic{*diag}
+			} else if err != nil {
+				pass.Reportf(d.Pos, "%s", err)
+				continue
+			}
+			for _, diag := range diags {
+				pass.Reportf(position(pass, diag.Pos), "%s", diag.Msg)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// position converts a position reported by the generator back to a
+// token.Pos of the pass.
+func position(pass *analysis.Pass, p token.Position) token.Pos {
+	for _, f := range pass.Files {
+		if tf := pass.Fset.File(f.Pos()); tf != nil && tf.Name() == p.Filename {
+			return tf.Pos(p.Offset)
+		}
+	}
+	return token.NoPos
+}
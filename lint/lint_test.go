@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// analyze runs a on pkgs and returns its diagnostics as "file:line: message".
+func analyze(t *testing.T, a *analysis.Analyzer, pkgs []*packages.Package) []string {
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			t.Fatal(act.Err)
+		}
+		for _, d := range act.Diagnostics {
+			pos := act.Package.Fset.Position(d.Pos)
+			got = append(got, fmt.Sprintf("%s:%d: %s", filepath.Base(pos.Filename), pos.Line, d.Message))
+		}
+	}
+	return got
+}
+
+// TestAnalyzer lists the expected diagnostics here rather than in want
+// comments, as those would become the messages of the constants.
+func TestAnalyzer(t *testing.T) {
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax}
+	pkgs, err := packages.Load(cfg, filepath.Join("testdata", "src", "lint", "errors.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := analyze(t, Analyzer, pkgs)
+	want := []string{
+		`errors.go:9: Conflict has the same message as NotFound: "User not found"`,
+		`errors.go:10: Missing has no message, so Error returns ""`,
+		`errors.go:11: message of Shouting ends with punctuation: "Too many requests!"`,
+		`errors.go:12: message of Quiet should start with an upper-case letter: "internal error"`,
+		`errors.go:14: Alias has the same value as NotFound, so String never returns Alias`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got diagnostics\n%q\nexpected\n%q", got, want)
+	}
+}
+
+func TestExhaustive(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Exhaustive, "handlers", "generated")
+
+	defaultExhaustive = true
+	defer func() { defaultExhaustive = false }()
+	analysistest.Run(t, analysistest.TestData(), Exhaustive, "defaults")
+}
@@ -0,0 +1,12 @@
+package codes
+
+//go:generate errorer -type=Error
+
+type Error int
+
+const (
+	NotFound Error = iota // Not found
+	Conflict              // Conflict
+	Internal              // Internal error
+	Missing  Error = 0    // Alias of NotFound
+)
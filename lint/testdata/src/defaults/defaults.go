@@ -0,0 +1,20 @@
+package defaults
+
+import "codes"
+
+func withDefault(err codes.Error) int {
+	switch err {
+	case codes.Conflict:
+		return 409
+	default:
+		return 500
+	}
+}
+
+func missing(err codes.Error) int {
+	switch err { // want "missing cases in switch of type codes.Error: NotFound, Internal"
+	case codes.Conflict:
+		return 409
+	}
+	return 0
+}
@@ -0,0 +1,18 @@
+package generated
+
+//go:generate errorer -type=Error
+
+type Error int // want Error:`errorer\(NotFound, Internal\)`
+
+const (
+	NotFound Error = iota //errorer:http=404 msg="Not found"
+	Internal              // Internal error
+)
+
+func status(err Error) int {
+	switch err { // want "missing cases in switch of type Error: Internal"
+	case NotFound:
+		return 404
+	}
+	return 0
+}
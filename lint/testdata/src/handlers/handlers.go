@@ -0,0 +1,92 @@
+package handlers
+
+import "codes"
+
+//go:generate errorer -type=Local
+
+type Local int // want Local:`errorer\(A, B\)`
+
+const (
+	A Local = iota // A
+	B              // B
+)
+
+func complete(err codes.Error) int {
+	switch err {
+	case codes.NotFound:
+		return 404
+	case codes.Conflict:
+		return 409
+	case codes.Internal:
+		return 500
+	}
+	return 0
+}
+
+func aliased(err codes.Error) int {
+	switch err {
+	case codes.Missing, codes.Conflict, codes.Internal:
+		return 1
+	}
+	return 0
+}
+
+func missing(err codes.Error) int {
+	switch err { // want "missing cases in switch of type codes.Error: NotFound, Internal"
+	case codes.Conflict:
+		return 409
+	}
+	return 0
+}
+
+func withDefault(err codes.Error) int {
+	switch err { // want "missing cases in switch of type codes.Error: NotFound, Internal"
+	case codes.Conflict:
+		return 409
+	default:
+		return 500
+	}
+}
+
+func ignored(err codes.Error) int {
+	//errorer:exhaustive-ignore
+	switch err {
+	case codes.Conflict:
+		return 409
+	}
+	return 0
+}
+
+func local(l Local) bool {
+	switch l { // want "missing cases in switch of type Local: B"
+	case A:
+		return true
+	}
+	return false
+}
+
+func untracked(n int) bool {
+	switch n {
+	case 1:
+		return true
+	}
+	return false
+}
+
+//go:generate errorer -type=Reason
+
+type Reason string // want Reason:`errorer\(Expired, Revoked, Gone\)`
+
+const (
+	Expired Reason = "token.expired" // Token expired
+	Revoked Reason = "token.revoked" // Token revoked
+	Gone    Reason = "token.expired" // Alias of Expired
+)
+
+func reason(r Reason) bool {
+	switch r { // want "missing cases in switch of type Reason: Revoked"
+	case Gone:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package lint
+
+//go:generate errorer -type=Error
+
+type Error int
+
+const (
+	NotFound Error = iota // User not found
+	Conflict              // User not found
+	Missing
+	Shouting            // Too many requests!
+	Quiet               // internal error
+	Templated           // User {id:string} not found (again)
+	Alias     Error = 0 // Same as NotFound
+)
+
+// Untracked has no directive, so it is not checked.
+type Untracked int
+
+const Silent Untracked = 1
@@ -0,0 +1,265 @@
+// Errorer generates String, Error and JSON methods for integer and string
+// error codes.
+// See github.com/iantanwx/errorer/generator for the generator itself.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iantanwx/errorer/generator"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_errors.go")
+	buildTags = flag.String("tags", "", "comma-separated list of build tags to apply")
+	modFlag   = flag.String("mod", "", "module download mode to use: readonly, vendor, or mod")
+	testFiles = flag.Bool("test", false, "include _test.go files; the output is then a _test.go file")
+	lint      = flag.Bool("lint", false, "report problems with the constants instead of generating code")
+	lintCase  = flag.String("lint-case", "upper", "case -lint expects messages to start with: upper, lower or any")
+	check     = flag.Bool("check", false, "report whether the output files are up to date instead of writing them")
+	catalog   = flag.String("catalog", "", "also write a catalog of the codes to this file; YAML if it ends in .yaml or .yml, JSON otherwise")
+	schema    = flag.String("schema", "", "also write a schema of the JSON envelope to this file")
+	schemaFmt = flag.String("schema-format", "jsonschema", "format of the -schema file: jsonschema or openapi")
+	tsOut     = flag.String("ts-out", "", "also write TypeScript definitions of the codes to this file")
+	protoOut  = flag.String("proto", "", "also write a proto3 enum of the codes to this file")
+	protoPkg  = flag.String("proto-package", "", "package of the -proto file")
+	protoDom  = flag.String("proto-domain", "", "generate ErrorInfo conversions using this error domain")
+	jsonShape = flag.String("json", "", "shape of the JSON envelope: empty for {type, message}, or rfc7807")
+	textFlag  = flag.Bool("text", false, "also generate MarshalText and UnmarshalText")
+	sqlMode   = flag.String("sql", "", "also generate Value and Scan storing the codes by name or int")
+	transform = flag.String("transform", "", "rewrite the names used by String and JSON: snake, kebab, upper-snake or camel")
+	ignCase   = flag.Bool("ignore-case", false, "make <Type>String also accept names differing in case and _ or - separators")
+	jsonKeys  = flag.String("json-fields", "", "comma-separated field:key pairs renaming the code and msg members of the JSON envelope")
+	lockFile  = flag.String("lock", "", "record the values of the codes in this file, failing if a recorded code changed or was removed")
+	allowBrk  = flag.Bool("allow-breaking", false, "with -lock, accept changed and removed codes and rewrite the lock file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("errorer: ")
+	flag.Parse()
+
+	if len(*typeNames) == 0 {
+		os.Exit(2)
+	}
+
+	types := strings.Split(*typeNames, ",")
+	// We accept either one directory or a list of files. Which do we have?
+	args := flag.Args()
+	if len(args) == 0 {
+		// Default: process whole package in current directory.
+		args = []string{"."}
+	}
+
+	cfg := generator.Config{
+		Types: types,
+		Args:  headerArgs(os.Args[1:]),
+		Mod:   *modFlag,
+		Tests: *testFiles,
+
+		ProtoDomain: *protoDom,
+		JSON:        generator.Envelope{Shape: *jsonShape},
+		Text:        *textFlag,
+		SQL:         *sqlMode,
+		Transform:   *transform,
+		IgnoreCase:  *ignCase,
+	}
+	if len(*jsonKeys) > 0 {
+		fields, err := generator.ParseEnvelopeFields(*jsonKeys)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.JSON.Fields = fields
+	}
+	if len(*buildTags) > 0 {
+		cfg.Tags = strings.Split(*buildTags, ",")
+	}
+	var dir string
+	if len(args) == 1 && isDirectory(args[0]) {
+		dir = args[0]
+		cfg.Dir = dir
+	} else {
+		dir = filepath.Dir(args[0])
+		cfg.Files = args
+	}
+
+	if *lint {
+		diags, err := generator.Lint(cfg, *lintCase)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, d := range diags {
+			fmt.Println(&d)
+		}
+		if len(diags) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	src, err := generator.Generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Write to file.
+	outputName := *output
+	if outputName == "" {
+		baseName := fmt.Sprintf("%s_string.go", types[0])
+		if *testFiles {
+			baseName = fmt.Sprintf("%s_string_test.go", types[0])
+		}
+		outputName = filepath.Join(dir, strings.ToLower(baseName))
+	}
+	outputs := []outputFile{{outputName, src}}
+
+	// The enums are loaded once, for all outputs and checks needing them.
+	var enums []generator.Enum
+	if *catalog != "" || *schema != "" || *tsOut != "" || *protoOut != "" || *lockFile != "" || *check {
+		enums, err = generator.Load(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *catalog != "" {
+			b := new(bytes.Buffer)
+			if err := generator.WriteCatalog(b, enums, generator.CatalogFormat(*catalog)); err != nil {
+				log.Fatal(err)
+			}
+			outputs = append(outputs, outputFile{*catalog, b.Bytes()})
+		}
+		if *schema != "" {
+			b := new(bytes.Buffer)
+			if err := generator.WriteSchema(b, enums, *schemaFmt, cfg.JSON); err != nil {
+				log.Fatal(err)
+			}
+			outputs = append(outputs, outputFile{*schema, b.Bytes()})
+		}
+		if *tsOut != "" {
+			b := new(bytes.Buffer)
+			if err := generator.WriteTypeScript(b, enums, cfg.JSON, cfg.Args); err != nil {
+				log.Fatal(err)
+			}
+			outputs = append(outputs, outputFile{*tsOut, b.Bytes()})
+		}
+		if *protoOut != "" {
+			b := new(bytes.Buffer)
+			if err := generator.WriteProto(b, enums, *protoPkg, cfg.Args); err != nil {
+				log.Fatal(err)
+			}
+			outputs = append(outputs, outputFile{*protoOut, b.Bytes()})
+		}
+		if *lockFile != "" {
+			outputs = append(outputs, outputFile{*lockFile, lock(enums)})
+		}
+	}
+
+	if *check {
+		// Untranslated messages fall back to other locales, so they
+		// are reported without failing the check.
+		for _, d := range generator.MissingTranslations(enums) {
+			log.Printf("warning: %s", &d)
+		}
+		checkOutputs(outputs)
+		return
+	}
+	for _, out := range outputs {
+		err = ioutil.WriteFile(out.name, out.data, 0644)
+		if err != nil {
+			log.Fatalf("writing output: %s", err)
+		}
+	}
+}
+
+// lock checks enums against the -lock file and returns its new contents.
+// Unless -allow-breaking is set, it exits with status 1 if a locked code
+// changed or was removed.
+func lock(enums []generator.Enum) []byte {
+	var locked []generator.LockEntry
+	f, err := os.Open(*lockFile)
+	switch {
+	case err == nil:
+		locked, err = generator.ParseLock(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %s", *lockFile, err)
+		}
+	case !os.IsNotExist(err):
+		log.Fatalf("reading lock file: %s", err)
+	}
+	if diags := generator.CheckLock(locked, enums); len(diags) > 0 {
+		for _, d := range diags {
+			log.Print(&d)
+		}
+		if !*allowBrk {
+			log.Fatalf("codes recorded in %s changed; renumber them back, or run with -allow-breaking to accept the change", *lockFile)
+		}
+	}
+	b := new(bytes.Buffer)
+	if err := generator.WriteLock(b, enums, locked); err != nil {
+		log.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// outputFile is a file produced by a run of errorer.
+type outputFile struct {
+	name string
+	data []byte
+}
+
+// headerArgs returns the command line to record in the generated header,
+// leaving out -check and -allow-breaking so that neither changes the output.
+func headerArgs(args []string) []string {
+	var kept []string
+	for _, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if i := strings.Index(name, "="); i >= 0 {
+			name = name[:i]
+		}
+		if (name == "check" || name == "allow-breaking") && strings.HasPrefix(arg, "-") {
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return kept
+}
+
+// checkOutputs compares the existing output files with the generated ones,
+// printing a unified diff for each that differs and exiting with status 1
+// if any do.
+func checkOutputs(outputs []outputFile) {
+	stale := false
+	for _, out := range outputs {
+		existing, err := ioutil.ReadFile(out.name)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatalf("reading output: %s", err)
+		}
+		diff := unifiedDiff(out.name, out.name+" (generated)", existing, out.data)
+		if diff == nil {
+			continue
+		}
+		os.Stdout.Write(diff)
+		log.Printf("%s is out of date; run go generate", out.name)
+		stale = true
+	}
+	if stale {
+		os.Exit(1)
+	}
+}
+
+// isDirectory reports whether the named file is a directory.
+func isDirectory(name string) bool {
+	info, err := os.Stat(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return info.IsDir()
+}
@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+type Annotated int
+
+const (
+	NotFound      Annotated = iota //errorer:http=404 grpc=NotFound msg="User could not be found"
+	AlreadyExists                  //errorer:http=409 grpc=6 code=1001 msg="User already exists"
+	Unannotated                    // Not sure what happened
+	Quoted                         //errorer:msg="Say \"hi\"" http=418
+)
+
+type coder interface {
+	Code() int
+	HTTPStatus() int
+	GRPCCode() uint32
+}
+
+func main() {
+	verify(NotFound, "User could not be found", 0, 404, 5)
+	verify(AlreadyExists, "User already exists", 1001, 409, 6)
+	verify(Unannotated, "Not sure what happened", 2, 500, 2)
+	verify(Quoted, `Say "hi"`, 3, 418, 2)
+}
+
+func verify(val interface{}, message string, code, status int, grpc uint32) {
+	if msg := val.(error).Error(); msg != message {
+		panic(fmt.Sprintf("wrong message %q, expected %q", msg, message))
+	}
+	c := val.(coder)
+	if c.Code() != code {
+		panic(fmt.Sprintf("%s: wrong code %d", message, c.Code()))
+	}
+	if c.HTTPStatus() != status {
+		panic(fmt.Sprintf("%s: wrong HTTP status %d", message, c.HTTPStatus()))
+	}
+	if c.GRPCCode() != grpc {
+		panic(fmt.Sprintf("%s: wrong gRPC code %d", message, c.GRPCCode()))
+	}
+}
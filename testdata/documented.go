@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+type Documented int
+
+const (
+	// User could not be found
+	NotFound Documented = iota
+
+	// The request conflicts with the
+	// current state of the user.
+	Conflict
+
+	// Doc comments give way to trailing ones.
+	Trailing // Trailing message
+
+	// Rate limit exceeded. Wait a minute
+	// before retrying.
+	//errorer:detail
+	RateLimited
+
+	//errorer:detail="Contact support."
+	Locked // Account locked
+
+	// Too many requests. Try again in {n:int} seconds.
+	//errorer:detail
+	Throttled
+)
+
+// Service is down for maintenance.
+const Maintenance Documented = 10
+
+func ck(code Documented, msg, detail string) {
+	if got := code.Error(); got != msg {
+		panic(fmt.Sprintf("%s.Error() = %q, want %q", code.String(), got, msg))
+	}
+	if got := code.Detail(); got != detail {
+		panic(fmt.Sprintf("%s.Detail() = %q, want %q", code.String(), got, detail))
+	}
+}
+
+func main() {
+	ck(NotFound, "User could not be found", "")
+	ck(Conflict, "The request conflicts with the current state of the user.", "")
+	ck(Trailing, "Trailing message", "")
+	ck(RateLimited, "Rate limit exceeded", "Wait a minute before retrying.")
+	ck(Locked, "Account locked", "Contact support.")
+	ck(Throttled, "Too many requests", "Try again in {n} seconds.")
+	ck(Maintenance, "Service is down for maintenance.", "")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Errinfo int
+
+const (
+	Unknown  Errinfo = iota // Unknown error
+	NotFound                // User could not be found
+	Conflict                // User already exists
+)
+
+func main() {
+	info := NotFound.ErrorInfo()
+	if info.Reason != "NotFound" || info.Domain != "errors.example.com" || info.Metadata != nil {
+		panic(fmt.Sprintf("wrong error info %+v", info))
+	}
+	code, err := ErrinfoFromErrorInfo(info)
+	if err != nil || code != NotFound {
+		panic(fmt.Sprintf("round trip gave %v, %v", code, err))
+	}
+
+	info = Conflict.Wrap(nil).With("user", 42).ErrorInfo()
+	if info.Reason != "Conflict" || info.Metadata["user"] != "42" {
+		panic(fmt.Sprintf("wrong wrapper error info %+v", info))
+	}
+
+	if _, err := ErrinfoFromErrorInfo(&ErrinfoErrorInfo{Reason: "NotFound", Domain: "other.example.com"}); err == nil || !strings.Contains(err.Error(), "other.example.com") {
+		panic(fmt.Sprintf("wrong error for another domain: %v", err))
+	}
+	if _, err := ErrinfoFromErrorInfo(&ErrinfoErrorInfo{Reason: "Gone", Domain: "errors.example.com"}); err == nil {
+		panic("no error for an unknown reason")
+	}
+	if _, err := ErrinfoFromErrorInfo(nil); err == nil {
+		panic("no error for nil error info")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import "fmt"
+
+type Localized int
+
+const (
+	NotFound Localized = iota //errorer:msg="User {id:string} not found" msg.de="Benutzer {id:string} nicht gefunden" msg.pt-BR="Usuário {id:string} não encontrado"
+	Conflict                  //errorer:msg="Conflict" msg.de="Konflikt" msg.pt="Conflito"
+	Internal                  // Internal error
+)
+
+// Values far apart, so the locale tables use the map layout.
+const (
+	Sparse0 Localized = 100 * (iota + 1) //errorer:msg="Sparse" msg.de="Verstreut"
+	Sparse1
+	Sparse2
+	Sparse3
+	Sparse4
+	Sparse5
+	Sparse6
+	Sparse7
+	Sparse8
+	Sparse9
+	Sparse10 // Sparse
+)
+
+func ck(code Localized, tag, want string) {
+	if got := code.Localize(tag); got != want {
+		panic(fmt.Sprintf("%s.Localize(%q) = %q, want %q", code.String(), tag, got, want))
+	}
+}
+
+func main() {
+	ck(NotFound, "de", "Benutzer {id} nicht gefunden")
+	ck(NotFound, "de-AT", "Benutzer {id} nicht gefunden")
+	ck(NotFound, "pt_br", "Usuário {id} não encontrado")
+	ck(NotFound, "pt", "User {id} not found")
+	ck(NotFound, "fr", "User {id} not found")
+	ck(NotFound, "", "User {id} not found")
+	ck(Conflict, "pt-BR", "Conflito")
+	ck(Conflict, "DE", "Konflikt")
+	ck(Internal, "de", "Internal error")
+	ck(Sparse0, "de", "Verstreut")
+	ck(Sparse10, "de", "Sparse")
+	ck(Localized(42), "de", "Localized(42)")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Multi int
+
+const (
+	NotFound      Multi = iota // User could not be found
+	AlreadyExists              // User already exists
+)
+
+type Status int
+
+const (
+	Pending  Status = iota + 1 // Request is pending
+	Rejected                   // Request was rejected
+)
+
+type Reason uint8
+
+const (
+	Expired Reason = 10 // Token has expired
+	Revoked Reason = 12 // Token was revoked
+)
+
+func main() {
+	verify(NotFound, new(Multi), "NotFound", "User could not be found")
+	verify(AlreadyExists, new(Multi), "AlreadyExists", "User already exists")
+	verify(Pending, new(Status), "Pending", "Request is pending")
+	verify(Rejected, new(Status), "Rejected", "Request was rejected")
+	verify(Expired, new(Reason), "Expired", "Token has expired")
+	verify(Revoked, new(Reason), "Revoked", "Token was revoked")
+
+	// Names are only valid for their own type.
+	var s Status
+	if json.Unmarshal([]byte(`"NotFound"`), &s) == nil {
+		panic("Status decoded a Multi name")
+	}
+}
+
+func verify(val, into interface{}, name, message string) {
+	err := val.(error)
+	if err.Error() != message {
+		panic(fmt.Sprintf("%s: wrong message %q", name, err.Error()))
+	}
+	if err.(fmt.Stringer).String() != name {
+		panic(fmt.Sprintf("%s: wrong name", name))
+	}
+	data, e := json.Marshal(err)
+	if e != nil {
+		panic(e)
+	}
+	if e := json.Unmarshal(data, into); e != nil {
+		panic(fmt.Sprintf("%s: %s", data, e))
+	}
+	if got := into.(fmt.Stringer).String(); got != name {
+		panic(fmt.Sprintf("%s: round trip got %s", name, got))
+	}
+}
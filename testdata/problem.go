@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Problem int
+
+const (
+	NotFound Problem = iota //errorer:http=404 msg="User not found"
+	Internal                // Internal error
+)
+
+func main() {
+	data, err := json.Marshal(NotFound)
+	if err != nil {
+		panic(err)
+	}
+	if string(data) != `{"type":"NotFound","title":"User not found","status":404}` {
+		panic("wrong JSON for a code: " + string(data))
+	}
+	var code Problem
+	if err := json.Unmarshal(data, &code); err != nil || code != NotFound {
+		panic(fmt.Sprintf("unmarshaling a code gave %v, %v", code, err))
+	}
+
+	data, err = json.Marshal(Internal.Wrap(io.EOF).With("instance", "/users/42"))
+	if err != nil {
+		panic(err)
+	}
+	if string(data) != `{"type":"Internal","title":"Internal error","status":500,"detail":"EOF","instance":"/users/42"}` {
+		panic("wrong JSON for a wrapped code: " + string(data))
+	}
+	var wrapped ProblemErr
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		panic(err)
+	}
+	if !errors.Is(&wrapped, Internal) || wrapped.Cause.Error() != "EOF" || wrapped.Fields["instance"] != "/users/42" {
+		panic(fmt.Sprintf("wrong wrapper %+v", wrapped))
+	}
+
+	data, _ = json.Marshal(NotFound.Wrap(nil))
+	if string(data) != `{"type":"NotFound","title":"User not found","status":404}` {
+		panic("wrong JSON for a wrapper without cause: " + string(data))
+	}
+	if err := json.Unmarshal([]byte(`{"type":"Gone"}`), &wrapped); err == nil {
+		panic("no error for an unknown type")
+	}
+	// null leaves a wrapper field alone, as it does a code.
+	var response struct {
+		Error ProblemErr `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(`{"error":null}`), &response); err != nil || response.Error.Cause != nil {
+		panic(fmt.Sprintf("unmarshaling null gave %+v, %v", response.Error, err))
+	}
+}
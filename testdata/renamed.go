@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Renamed int
+
+const (
+	NotFound Renamed = iota // User not found
+	Conflict                // User already exists
+)
+
+func main() {
+	data, err := json.Marshal(Conflict)
+	if err != nil {
+		panic(err)
+	}
+	if string(data) != `{"error":"Conflict","detail":"User already exists"}` {
+		panic("wrong JSON: " + string(data))
+	}
+	var code Renamed
+	if err := json.Unmarshal(data, &code); err != nil || code != Conflict {
+		panic(fmt.Sprintf("unmarshaling gave %v, %v", code, err))
+	}
+	// The default key is not recognized.
+	if err := json.Unmarshal([]byte(`{"type":"Conflict"}`), &code); err == nil {
+		panic("unmarshaled the default key")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Roundtrip int
+
+const (
+	NotFound         Roundtrip = iota // User could not be found
+	AlreadyExists                     // User already exists
+	NotSure                           // Not sure what happened
+	BadRequestData                    // You didn't send a good request
+	WorksOnMyMachine                  // Works on my machine
+)
+
+type envelope struct {
+	Err Roundtrip `json:"err"`
+}
+
+func main() {
+	for _, err := range []Roundtrip{NotFound, AlreadyExists, NotSure, BadRequestData, WorksOnMyMachine} {
+		roundtrip(err)
+	}
+
+	var err Roundtrip
+	if json.Unmarshal([]byte(`"NotSure"`), &err) != nil || err != NotSure {
+		panic("bare string did not decode")
+	}
+
+	var env envelope
+	if json.Unmarshal([]byte(`{"err":{"type":"AlreadyExists","message":"User already exists"}}`), &env) != nil || env.Err != AlreadyExists {
+		panic("nested envelope did not decode")
+	}
+
+	unknown := json.Unmarshal([]byte(`{"type":"Missing","message":"?"}`), &err)
+	if typ := fmt.Sprintf("%T", unknown); typ != "main.RoundtripNameError" {
+		panic("expected a RoundtripNameError, got " + typ)
+	}
+	if unknown.Error() != `"Missing" is not the name of type Roundtrip` {
+		panic("wrong unknown name message: " + unknown.Error())
+	}
+
+	if json.Unmarshal([]byte(`42`), &err) == nil {
+		panic("expected an error decoding a number")
+	}
+}
+
+func roundtrip(err Roundtrip) {
+	data, e := json.Marshal(err)
+	if e != nil {
+		panic(e)
+	}
+	var got Roundtrip
+	if e := json.Unmarshal(data, &got); e != nil {
+		panic(fmt.Sprintf("%s: %s", data, e))
+	}
+	if got != err {
+		panic(fmt.Sprintf("%s: got %d, expected %d", data, got, err))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// More than ten runs, so String and Error are looked up in maps.
+type Sparse int
+
+const (
+	BadRequest         Sparse = 400 // Bad request
+	Unauthorized       Sparse = 401 // Unauthorized
+	NotFound           Sparse = 404 // Not found
+	Conflict           Sparse = 409 // Conflict
+	PreconditionFailed Sparse = 412 // Precondition failed
+	UnsupportedMedia   Sparse = 415 // Unsupported media type
+	Teapot             Sparse = 418 // I'm a teapot
+	Unprocessable      Sparse = 422 // Unprocessable entity
+	TooManyRequests    Sparse = 429 // Too many requests
+	Internal           Sparse = 500 // Internal server error
+	Unavailable        Sparse = 503 // Service unavailable
+	Timeout            Sparse = 504 // Gateway timeout
+)
+
+func main() {
+	verify(BadRequest, "BadRequest", "Bad request")
+	verify(Unauthorized, "Unauthorized", "Unauthorized")
+	verify(NotFound, "NotFound", "Not found")
+	verify(Conflict, "Conflict", "Conflict")
+	verify(PreconditionFailed, "PreconditionFailed", "Precondition failed")
+	verify(UnsupportedMedia, "UnsupportedMedia", "Unsupported media type")
+	verify(Teapot, "Teapot", "I'm a teapot")
+	verify(Unprocessable, "Unprocessable", "Unprocessable entity")
+	verify(TooManyRequests, "TooManyRequests", "Too many requests")
+	verify(Internal, "Internal", "Internal server error")
+	verify(Unavailable, "Unavailable", "Service unavailable")
+	verify(Timeout, "Timeout", "Gateway timeout")
+
+	for _, unknown := range []interface{}{Sparse(402), Sparse(0)} {
+		want := fmt.Sprintf("Sparse(%d)", unknown)
+		if unknown.(fmt.Stringer).String() != want || unknown.(error).Error() != want {
+			panic("wrong fallback for " + want)
+		}
+	}
+}
+
+func verify(val interface{}, name, message string) {
+	if got := val.(error).Error(); got != message {
+		panic(fmt.Sprintf("%s: got message %q, expected %q", name, got, message))
+	}
+	if got := val.(fmt.Stringer).String(); got != name {
+		panic(fmt.Sprintf("got name %q, expected %q", got, name))
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		panic(err)
+	}
+	var back Sparse
+	if err := json.Unmarshal(data, &back); err != nil || back != val {
+		panic(fmt.Sprintf("%s did not round trip: %v", data, err))
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Sqlint uint8
+
+const (
+	NotFound Sqlint = iota + 1 // Not found
+	Conflict                   // Conflict
+	Internal Sqlint = 200      // Internal error
+)
+
+func expectError(err error, want string) {
+	if err == nil || !strings.Contains(err.Error(), want) {
+		panic(fmt.Sprintf("got error %v, want one containing %q", err, want))
+	}
+}
+
+func main() {
+	v, err := Internal.Value()
+	if err != nil || v != int64(200) {
+		panic(fmt.Sprintf("Value gave %v, %v", v, err))
+	}
+	_, err = Sqlint(9).Value()
+	expectError(err, "9 is not a Sqlint")
+
+	var code Sqlint
+	if err := code.Scan(int64(2)); err != nil || code != Conflict {
+		panic(fmt.Sprintf("Scan gave %v, %v", code, err))
+	}
+	if err := code.Scan([]byte("200")); err != nil || code != Internal {
+		panic(fmt.Sprintf("Scan of bytes gave %v, %v", code, err))
+	}
+	if err := code.Scan("1"); err != nil || code != NotFound {
+		panic(fmt.Sprintf("Scan of a string gave %v, %v", code, err))
+	}
+	expectError(code.Scan(int64(9)), "9 is not a Sqlint")
+	// 257 would truncate to NotFound.
+	expectError(code.Scan(int64(257)), "257 is not a Sqlint")
+	expectError(code.Scan("one"), `"one" is not an integer`)
+	expectError(code.Scan(1.5), "cannot convert float64")
+	expectError(code.Scan(nil), "NULL")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+type Sqlname uint8
+
+const (
+	NotFound Sqlname = iota + 1 // Not found
+	Conflict                    // Conflict
+)
+
+var (
+	_ driver.Valuer = NotFound
+	_ sql.Scanner   = new(Sqlname)
+)
+
+func expectError(err error, want string) {
+	if err == nil || !strings.Contains(err.Error(), want) {
+		panic(fmt.Sprintf("got error %v, want one containing %q", err, want))
+	}
+}
+
+func main() {
+	v, err := Conflict.Value()
+	if err != nil || v != "Conflict" {
+		panic(fmt.Sprintf("Value gave %v, %v", v, err))
+	}
+	_, err = Sqlname(9).Value()
+	expectError(err, "9 has no name")
+
+	var code Sqlname
+	if err := code.Scan("NotFound"); err != nil || code != NotFound {
+		panic(fmt.Sprintf("Scan gave %v, %v", code, err))
+	}
+	if err := code.Scan([]byte("Conflict")); err != nil || code != Conflict {
+		panic(fmt.Sprintf("Scan of bytes gave %v, %v", code, err))
+	}
+	expectError(code.Scan("Gone"), `"Gone" is not the name of a Sqlname`)
+	expectError(code.Scan(int64(1)), "cannot convert int64")
+	expectError(code.Scan(nil), "NULL")
+	if code != Conflict {
+		panic("failed Scan changed the value")
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Strcode string
+
+const (
+	UserNotFound Strcode = "user.not_found" //errorer:http=404 msg="User {id:string} not found"
+	UserExists   Strcode = "user.exists"    //errorer:http=409 msg="User already exists"
+	// Internal error. Retry later.
+	//errorer:detail msg.de="Interner Fehler"
+	Internal Strcode = "internal"
+	Unknown  Strcode = "internal" // Alias of Internal
+)
+
+var _ driver.Valuer = Internal
+
+func ck(code Strcode, str, msg string) {
+	if code.String() != str {
+		panic(fmt.Sprintf("Strcode.String(%q) = %q, want %q", string(code), code.String(), str))
+	}
+	if code.Error() != msg {
+		panic(fmt.Sprintf("Strcode.Error(%q) = %q, want %q", string(code), code.Error(), msg))
+	}
+	data, err := json.Marshal(code)
+	want := fmt.Sprintf(`{"type":%q,"message":%q}`, string(code), msg)
+	if err != nil || string(data) != want {
+		panic(fmt.Sprintf("MarshalJSON gave %s, %v; want %s", data, err, want))
+	}
+	var back Strcode
+	if err := json.Unmarshal(data, &back); err != nil || back != code {
+		panic(fmt.Sprintf("UnmarshalJSON gave %q, %v", string(back), err))
+	}
+}
+
+func main() {
+	ck(UserNotFound, "UserNotFound", "User {id} not found")
+	ck(UserExists, "UserExists", "User already exists")
+	ck(Internal, "Internal", "Internal error")
+	ck(Unknown, "Internal", "Internal error")
+	if s := Strcode("gone").String(); s != `Strcode("gone")` {
+		panic(fmt.Sprintf("String of an unknown code gave %q", s))
+	}
+
+	if code, err := StrcodeString("user.exists"); err != nil || code != UserExists {
+		panic(fmt.Sprintf("StrcodeString gave %q, %v", string(code), err))
+	}
+	if code, err := StrcodeString("User.Exists"); err != nil || code != UserExists {
+		panic(fmt.Sprintf("StrcodeString ignoring case gave %q, %v", string(code), err))
+	}
+	if _, err := StrcodeString("UserExists"); err == nil {
+		panic("StrcodeString accepted a constant name")
+	}
+
+	if d := Internal.Detail(); d != "Retry later." {
+		panic(fmt.Sprintf("Detail gave %q", d))
+	}
+	if m := Internal.Localize("de-AT"); m != "Interner Fehler" {
+		panic(fmt.Sprintf("Localize gave %q", m))
+	}
+	if UserNotFound.HTTPStatus() != 404 || Internal.HTTPStatus() != 500 {
+		panic("wrong code methods")
+	}
+	// Not every constant has a code annotation, so there is no Code method.
+	if _, ok := interface{}(Internal).(interface{ Code() int }); ok {
+		panic("Code generated without code annotations")
+	}
+	args := UserNotFoundf("42")
+	if args.Error() != "User 42 not found" || !errors.Is(args, UserNotFound) {
+		panic(fmt.Sprintf("template gave %q", args.Error()))
+	}
+
+	values := StrcodeValues()
+	if len(values) != 3 || values[0] != Internal || values[1] != UserExists || values[2] != UserNotFound {
+		panic(fmt.Sprintf("StrcodeValues gave %q", values))
+	}
+	if names := strings.Join(StrcodeNames(), ","); names != "Internal,UserExists,UserNotFound" {
+		panic(fmt.Sprintf("StrcodeNames gave %s", names))
+	}
+	if !Unknown.IsValid() || Strcode("gone").IsValid() {
+		panic("wrong IsValid")
+	}
+
+	text, err := UserExists.MarshalText()
+	if err != nil || string(text) != "user.exists" {
+		panic(fmt.Sprintf("MarshalText gave %s, %v", text, err))
+	}
+	v, err := UserExists.Value()
+	if err != nil || v != "user.exists" {
+		panic(fmt.Sprintf("Value gave %v, %v", v, err))
+	}
+	if _, err := Strcode("gone").Value(); err == nil {
+		panic("Value accepted an unknown code")
+	}
+	var code Strcode
+	if err := code.Scan([]byte("internal")); err != nil || code != Internal {
+		panic(fmt.Sprintf("Scan gave %q, %v", string(code), err))
+	}
+	if err := code.Scan("UserExists"); err == nil {
+		panic("Scan accepted a constant name")
+	}
+
+	info := UserExists.Wrap(nil).ErrorInfo()
+	if info.Reason != "user.exists" {
+		panic(fmt.Sprintf("wrong error info %+v", info))
+	}
+	if code, err := StrcodeFromErrorInfo(info); err != nil || code != UserExists {
+		panic(fmt.Sprintf("round trip gave %q, %v", string(code), err))
+	}
+}
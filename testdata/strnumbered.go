@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+type Strnumbered string
+
+const (
+	Expired Strnumbered = "token.expired" //errorer:code=4001 http=401 msg="Token expired"
+	Revoked Strnumbered = "token.revoked" //errorer:code=4002 http=403 msg="Token revoked"
+)
+
+func main() {
+	if Expired.Code() != 4001 || Revoked.Code() != 4002 {
+		panic(fmt.Sprintf("wrong codes %d, %d", Expired.Code(), Revoked.Code()))
+	}
+	if code := Strnumbered("gone").Code(); code != -1 {
+		panic(fmt.Sprintf("Code of an unknown value gave %d", code))
+	}
+	if Revoked.HTTPStatus() != 403 {
+		panic("wrong HTTP status")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Templated int
+
+const (
+	NotFound Templated = iota // User {id:string} could not be found in {org:string}
+	TooMany                   // Made {count:int} requests in {window:time.Duration}, 100% of quota
+	Static                    // Not sure what happened
+)
+
+func main() {
+	if NotFound.Error() != "User {id} could not be found in {org}" {
+		panic("wrong plain message: " + NotFound.Error())
+	}
+	if Static.Error() != "Not sure what happened" {
+		panic("wrong static message: " + Static.Error())
+	}
+
+	var err error = NotFoundf("u1", "acme")
+	if err.Error() != "User u1 could not be found in acme" {
+		panic("wrong rendered message: " + err.Error())
+	}
+	if !errors.Is(err, NotFound) {
+		panic("errors.Is did not match NotFound")
+	}
+	if errors.Is(err, Static) {
+		panic("errors.Is matched Static")
+	}
+	if !errors.Is(fmt.Errorf("lookup: %w", err), NotFound) {
+		panic("errors.Is did not match through %w")
+	}
+
+	err = TooManyf(120, time.Minute)
+	if err.Error() != "Made 120 requests in 1m0s, 100% of quota" {
+		panic("wrong rendered message: " + err.Error())
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+type Text int
+
+const (
+	NotFound Text = iota + 1 // Not found
+	Conflict                 // Conflict
+)
+
+func main() {
+	data, err := json.Marshal(map[Text]int{NotFound: 1, Conflict: 2})
+	if err != nil {
+		panic(err)
+	}
+	if string(data) != `{"Conflict":2,"NotFound":1}` {
+		panic("wrong map keys: " + string(data))
+	}
+	var counts map[Text]int
+	if err := json.Unmarshal(data, &counts); err != nil {
+		panic(err)
+	}
+	if len(counts) != 2 || counts[NotFound] != 1 || counts[Conflict] != 2 {
+		panic(fmt.Sprintf("wrong map %v", counts))
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var code Text
+	fs.TextVar(&code, "code", NotFound, "error code")
+	if err := fs.Parse([]string{"-code=Conflict"}); err != nil {
+		panic(err)
+	}
+	if code != Conflict {
+		panic(fmt.Sprintf("flag gave %v", code))
+	}
+	if err := code.UnmarshalText([]byte("Gone")); err == nil {
+		panic("no error for an unknown name")
+	}
+
+	if _, err := Text(0).MarshalText(); err == nil {
+		panic("no error for a value without a name")
+	}
+	if _, err := json.Marshal(map[Text]int{Text(7): 1}); err == nil {
+		panic("no error for a map key without a name")
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type CamelCaseValue int
+
+const (
+	NotFound    CamelCaseValue = iota // Not found
+	HTTPTimeout                       // Timed out
+	Retry2Later                       // Try again later
+)
+
+func main() {
+	for code, want := range map[CamelCaseValue]string{
+		NotFound:    "not_found",
+		HTTPTimeout: "http_timeout",
+		Retry2Later: "retry2_later",
+	} {
+		if got := code.String(); got != want {
+			panic(fmt.Sprintf("String() = %q, want %q", got, want))
+		}
+	}
+	if got := CamelCaseValue(7).String(); got != "CamelCaseValue(7)" {
+		panic("wrong String for an unknown value: " + got)
+	}
+
+	data, err := json.Marshal(HTTPTimeout)
+	if err != nil {
+		panic(err)
+	}
+	if string(data) != `{"type":"http_timeout","message":"Timed out"}` {
+		panic("wrong JSON: " + string(data))
+	}
+	var code CamelCaseValue
+	if err := json.Unmarshal(data, &code); err != nil || code != HTTPTimeout {
+		panic(fmt.Sprintf("unmarshaling gave %v, %v", code, err))
+	}
+
+	// -ignore-case accepts the Go names and other spellings.
+	for _, name := range []string{"not_found", "NotFound", "NOT_FOUND", "not-found", "notfound"} {
+		if got, err := CamelCaseValueString(name); err != nil || got != NotFound {
+			panic(fmt.Sprintf("CamelCaseValueString(%q) = %v, %v", name, got, err))
+		}
+	}
+	if _, err := CamelCaseValueString("not found"); err == nil {
+		panic("no error for a name with a space")
+	}
+
+	// ErrorInfo reasons keep the constant names.
+	info := HTTPTimeout.ErrorInfo()
+	if info.Reason != "HTTPTimeout" {
+		panic(fmt.Sprintf("wrong error info %+v", info))
+	}
+	if code, err := CamelCaseValueFromErrorInfo(info); err != nil || code != HTTPTimeout {
+		panic(fmt.Sprintf("round trip gave %v, %v", code, err))
+	}
+	info.Reason = "http_timeout"
+	if _, err := CamelCaseValueFromErrorInfo(info); err == nil {
+		panic("no error for a transformed reason")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type Values uint16
+
+// More than ten runs, so the tables are maps.
+const (
+	V1    Values = 1    // One
+	V3    Values = 3    // Three
+	V5    Values = 5    // Five
+	V7    Values = 7    // Seven
+	V9    Values = 9    // Nine
+	V11   Values = 11   // Eleven
+	V13   Values = 13   // Thirteen
+	V15   Values = 15   // Fifteen
+	V17   Values = 17   // Seventeen
+	V19   Values = 19   // Nineteen
+	V1000 Values = 1000 // Thousand
+	Alias Values = 3    // Same as V3
+	V0    Values = 0    // Zero
+)
+
+func main() {
+	want := []Values{V0, V1, V3, V5, V7, V9, V11, V13, V15, V17, V19, V1000}
+	if got := ValuesValues(); !reflect.DeepEqual(got, want) {
+		panic(fmt.Sprintf("ValuesValues() = %v", got))
+	}
+	names := []string{"V0", "V1", "V3", "V5", "V7", "V9", "V11", "V13", "V15", "V17", "V19", "V1000"}
+	if got := ValuesNames(); !reflect.DeepEqual(got, names) {
+		panic(fmt.Sprintf("ValuesNames() = %v", got))
+	}
+	// The result is a copy.
+	ValuesValues()[0] = V1
+	if ValuesValues()[0] != V0 {
+		panic("ValuesValues returned its table")
+	}
+
+	for _, v := range want {
+		if !v.IsValid() {
+			panic(fmt.Sprintf("%v is not valid", v))
+		}
+	}
+	for _, v := range []Values{2, 4, 20, 999, 1001, 65535} {
+		if v.IsValid() {
+			panic(fmt.Sprintf("%d is valid", v))
+		}
+	}
+}
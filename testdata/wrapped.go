@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Wrapped int
+
+const (
+	NotFound      Wrapped = iota // User could not be found
+	AlreadyExists                // User already exists
+)
+
+func main() {
+	var err error = NotFound.Wrap(io.EOF).With("user", "u1")
+	if err.Error() != "User could not be found: EOF" {
+		panic("wrong message: " + err.Error())
+	}
+	if !errors.Is(err, NotFound) {
+		panic("errors.Is did not match the code")
+	}
+	if errors.Is(err, AlreadyExists) {
+		panic("errors.Is matched another code")
+	}
+	if !errors.Is(err, io.EOF) {
+		panic("errors.Is did not match the cause")
+	}
+
+	chained := fmt.Errorf("handler: %w", fmt.Errorf("store: %w", err))
+	if !errors.Is(chained, NotFound) {
+		panic("errors.Is did not match through %w chains")
+	}
+	// Wrapper targets match by code only.
+	if !errors.Is(chained, NotFound.Wrap(nil)) || errors.Is(chained, AlreadyExists.Wrap(nil)) {
+		panic("wrapper targets should match by code")
+	}
+
+	var wrapped *WrappedErr
+	if !errors.As(chained, &wrapped) {
+		panic("errors.As did not find the wrapper")
+	}
+	if wrapped.Code != NotFound || wrapped.Fields["user"] != "u1" {
+		panic(fmt.Sprintf("wrong wrapper %+v", wrapped))
+	}
+
+	if err := AlreadyExists.Wrap(nil); err.Error() != "User already exists" {
+		panic("wrong message without cause: " + err.Error())
+	}
+}